@@ -0,0 +1,28 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+)
+
+// New returns the http handler served alongside the grpc server
+func New(keys *utils.KeySet) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/jwks.json", jwksHandler(keys))
+	return mux
+}
+
+func jwksHandler(keys *utils.KeySet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		json.NewEncoder(w).Encode(keys.JWKS())
+	}
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 
 	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
 	"github.com/go-redis/redis/v9"
@@ -12,12 +14,14 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/TemurMannonov/medium_user_service/api"
 	"github.com/TemurMannonov/medium_user_service/config"
 	"github.com/TemurMannonov/medium_user_service/service"
 	"github.com/TemurMannonov/medium_user_service/storage"
 
 	grpcPkg "github.com/TemurMannonov/medium_user_service/pkg/grpc_client"
 	"github.com/TemurMannonov/medium_user_service/pkg/logger"
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
 )
 
 func main() {
@@ -49,19 +53,53 @@ func main() {
 	}
 	logrus := logger.New()
 
-	userService := service.NewUserService(strg, inMemory, logrus)
-	authService := service.NewAuthService(strg, inMemory, grpcConn, &cfg, logrus)
+	keys, err := utils.NewKeySet(&cfg)
+	if err != nil {
+		log.Fatalf("failed to load auth keys: %v", err)
+	}
+
+	trustedProxies, err := utils.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("failed to parse trusted proxies: %v", err)
+	}
+
+	authService := service.NewAuthService(strg, inMemory, grpcConn, keys, trustedProxies, &cfg, logrus)
+	userService := service.NewUserService(strg, inMemory, authService, &cfg, logrus)
+	permissionService := service.NewPermissionService(strg, authService, logrus)
+	webhookService := service.NewWebhookService(strg, authService, logrus)
+
+	outboxDispatcher := service.NewOutboxDispatcher(strg, grpcConn, &cfg, logrus)
+	webhookDispatcher := service.NewWebhookDispatcher(strg, &cfg, logrus)
+
+	go userService.RunPurgeWorker(context.Background())
+	go outboxDispatcher.Run(context.Background())
+	go webhookDispatcher.Run(context.Background())
+
+	go func() {
+		log.Println("Http server started in port ", cfg.HttpPort)
+		if err := http.ListenAndServe(cfg.HttpPort, api.New(keys)); err != nil {
+			log.Fatalf("Error while listening http: %v", err)
+		}
+	}()
 
 	lis, err := net.Listen("tcp", cfg.GrpcPort)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer()
+	if cfg.IdempotencySecretKey == "" {
+		log.Fatal("IDEMPOTENCY_SECRET_KEY is not set")
+	}
+
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(service.IdempotencyInterceptor(inMemory, authService, []byte(cfg.IdempotencySecretKey), logrus)),
+	)
 	reflection.Register(s)
 
 	pb.RegisterUserServiceServer(s, userService)
 	pb.RegisterAuthServiceServer(s, authService)
+	pb.RegisterPermissionServiceServer(s, permissionService)
+	pb.RegisterWebhookServiceServer(s, webhookService)
 
 	log.Println("Grpc server started in port ", cfg.GrpcPort)
 	if err := s.Serve(lis); err != nil {
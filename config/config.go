@@ -1,16 +1,44 @@
 package config
 
 import (
+	"strings"
+	"time"
+
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	GrpcPort      string
+	HttpPort      string
 	Postgres      PostgresConfig
 	Redis         Redis
 	AuthSecretKey string
 
+	AuthKeysDir      string
+	AuthSigningKeyID string
+	// AuthAcceptHS256Tokens keeps the tokens signed with AuthSecretKey valid after the switch
+	// to AuthKeysDir, it's meant for the migration and is off by default
+	AuthAcceptHS256Tokens bool
+
+	AccessTokenDuration  time.Duration
+	RefreshTokenDuration time.Duration
+
+	InternalServiceToken string
+	// IdempotencySecretKey keys the hashes of the requests stored with their idempotency key
+	IdempotencySecretKey string
+	// TrustedProxies lists the ips and CIDR ranges allowed to set x-forwarded-for
+	TrustedProxies []string
+
+	UserPurgeGracePeriod time.Duration
+	UserPurgeInterval    time.Duration
+
+	OutboxPollInterval time.Duration
+	OutboxMaxAttempts  int
+
+	WebhookTimeout     time.Duration
+	WebhookMaxAttempts int
+
 	NotificationServiceGrpcPort string
 	NotificationServiceHost     string
 }
@@ -33,8 +61,18 @@ func Load(path string) Config {
 	conf := viper.New()
 	conf.AutomaticEnv()
 
+	conf.SetDefault("ACCESS_TOKEN_DURATION", "15m")
+	conf.SetDefault("REFRESH_TOKEN_DURATION", "720h")
+	conf.SetDefault("USER_PURGE_GRACE_PERIOD", "720h")
+	conf.SetDefault("USER_PURGE_INTERVAL", "1h")
+	conf.SetDefault("OUTBOX_POLL_INTERVAL", "1s")
+	conf.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	conf.SetDefault("WEBHOOK_TIMEOUT", "10s")
+	conf.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+
 	cfg := Config{
 		GrpcPort: conf.GetString("GRPC_PORT"),
+		HttpPort: conf.GetString("HTTP_PORT"),
 		Postgres: PostgresConfig{
 			Host:     conf.GetString("POSTGRES_HOST"),
 			Port:     conf.GetString("POSTGRES_PORT"),
@@ -46,6 +84,20 @@ func Load(path string) Config {
 			Addr: conf.GetString("REDIS_ADDR"),
 		},
 		AuthSecretKey:               conf.GetString("AUTH_SECRET_KEY"),
+		AuthKeysDir:                 conf.GetString("AUTH_KEYS_DIR"),
+		AuthSigningKeyID:            conf.GetString("AUTH_SIGNING_KEY_ID"),
+		AuthAcceptHS256Tokens:       conf.GetBool("AUTH_ACCEPT_HS256_TOKENS"),
+		AccessTokenDuration:         conf.GetDuration("ACCESS_TOKEN_DURATION"),
+		RefreshTokenDuration:        conf.GetDuration("REFRESH_TOKEN_DURATION"),
+		InternalServiceToken:        conf.GetString("INTERNAL_SERVICE_TOKEN"),
+		IdempotencySecretKey:        conf.GetString("IDEMPOTENCY_SECRET_KEY"),
+		TrustedProxies:              strings.Split(conf.GetString("TRUSTED_PROXIES"), ","),
+		UserPurgeGracePeriod:        conf.GetDuration("USER_PURGE_GRACE_PERIOD"),
+		UserPurgeInterval:           conf.GetDuration("USER_PURGE_INTERVAL"),
+		OutboxPollInterval:          conf.GetDuration("OUTBOX_POLL_INTERVAL"),
+		OutboxMaxAttempts:           conf.GetInt("OUTBOX_MAX_ATTEMPTS"),
+		WebhookTimeout:              conf.GetDuration("WEBHOOK_TIMEOUT"),
+		WebhookMaxAttempts:          conf.GetInt("WEBHOOK_MAX_ATTEMPTS"),
 		NotificationServiceHost:     conf.GetString("NOTIFICATION_SERVICE_HOST"),
 		NotificationServiceGrpcPort: conf.GetString("NOTIFICATION_SERVICE_GRPC_PORT"),
 	}
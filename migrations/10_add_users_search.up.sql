@@ -0,0 +1,10 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS "search_vector" TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', first_name || ' ' || last_name), 'A') ||
+    setweight(to_tsvector('simple', coalesce(username, '')), 'A')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS users_search_vector_idx ON users USING GIN(search_vector);
+CREATE INDEX IF NOT EXISTS users_full_name_trgm_idx ON users USING GIN((first_name || ' ' || last_name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS users_username_trgm_idx ON users USING GIN(username gin_trgm_ops);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS "follows"(
+    "follower_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "followee_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(follower_id, followee_id),
+    CHECK(follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS follows_followee_id_idx ON follows(followee_id, created_at, follower_id);
+CREATE INDEX IF NOT EXISTS follows_follower_id_idx ON follows(follower_id, created_at, followee_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS "followers_count" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS "following_count" INTEGER NOT NULL DEFAULT 0;
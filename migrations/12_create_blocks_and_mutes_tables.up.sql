@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS "blocks"(
+    "blocker_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "blocked_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(blocker_id, blocked_id),
+    CHECK(blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS blocks_blocker_id_idx ON blocks(blocker_id, created_at, blocked_id);
+CREATE INDEX IF NOT EXISTS blocks_blocked_id_idx ON blocks(blocked_id);
+
+CREATE TABLE IF NOT EXISTS "mutes"(
+    "muter_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "muted_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(muter_id, muted_id),
+    CHECK(muter_id <> muted_id)
+);
+
+CREATE INDEX IF NOT EXISTS mutes_muter_id_idx ON mutes(muter_id, created_at, muted_id);
+CREATE INDEX IF NOT EXISTS mutes_muted_id_idx ON mutes(muted_id);
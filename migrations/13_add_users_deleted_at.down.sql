@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS users_follows_counted_idx;
+DROP INDEX IF EXISTS users_deleted_at_idx;
+
+ALTER TABLE users DROP COLUMN IF EXISTS "follows_counted";
+ALTER TABLE users DROP COLUMN IF EXISTS "deleted_at";
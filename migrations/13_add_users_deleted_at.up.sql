@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMP WITH TIME ZONE;
+-- follows_counted tells whether the follows of the user are in the counts of the users on the other side,
+-- it catches up with deleted_at in the background
+ALTER TABLE users ADD COLUMN IF NOT EXISTS "follows_counted" BOOLEAN NOT NULL DEFAULT true;
+
+CREATE INDEX IF NOT EXISTS users_deleted_at_idx ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS users_follows_counted_idx ON users(id) WHERE follows_counted = (deleted_at IS NOT NULL);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS users_status_idx;
+
+ALTER TABLE users DROP COLUMN IF EXISTS "status_expires_at";
+ALTER TABLE users DROP COLUMN IF EXISTS "status_changed_by";
+ALTER TABLE users DROP COLUMN IF EXISTS "status_reason";
+ALTER TABLE users DROP COLUMN IF EXISTS "status";
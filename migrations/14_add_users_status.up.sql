@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS "status" VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK ("status" IN('active', 'pending', 'suspended', 'banned', 'deactivated'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS "status_reason" VARCHAR;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS "status_changed_by" INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS "status_expires_at" TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS users_status_idx ON users(status) WHERE status <> 'active';
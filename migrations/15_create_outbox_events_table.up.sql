@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS "outbox_events"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "event_id" UUID NOT NULL UNIQUE,
+    "type" VARCHAR(100) NOT NULL,
+    "user_id" BIGINT,
+    "payload" JSONB NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK ("status" IN('pending', 'sent', 'dead')),
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "last_error" TEXT,
+    "next_attempt_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "sent_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS outbox_events_pending_idx ON outbox_events(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS outbox_events_dead_idx ON outbox_events(id) WHERE status = 'dead';
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "user_events"(
+    "sequence" BIGSERIAL PRIMARY KEY,
+    "event_id" UUID NOT NULL UNIQUE,
+    "type" VARCHAR(100) NOT NULL,
+    "user_id" BIGINT NOT NULL,
+    "payload" JSONB NOT NULL,
+    -- the transaction writing the event, the log is read in the transaction order
+    "xact_id" XID8 NOT NULL DEFAULT pg_current_xact_id(),
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS user_events_xact_id_idx ON user_events(xact_id, sequence);
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS "webhooks"(
+    "id" SERIAL PRIMARY KEY,
+    "url" VARCHAR NOT NULL,
+    "secret" VARCHAR NOT NULL,
+    "event_types" VARCHAR(100)[] NOT NULL,
+    "active" BOOLEAN NOT NULL DEFAULT true,
+    "created_by" INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "webhook_deliveries"(
+    "id" BIGSERIAL PRIMARY KEY,
+    "webhook_id" INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    "event_id" UUID NOT NULL,
+    "event_type" VARCHAR(100) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK ("status" IN('pending', 'succeeded', 'failed')),
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "response_status" INTEGER,
+    "last_error" TEXT,
+    "next_attempt_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "delivered_at" TIMESTAMP WITH TIME ZONE,
+    UNIQUE(webhook_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries(webhook_id, id);
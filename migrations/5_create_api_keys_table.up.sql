@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS "api_keys"(
+    "id" SERIAL PRIMARY KEY,
+    "user_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "name" VARCHAR(100) NOT NULL,
+    "prefix" VARCHAR(16) NOT NULL,
+    "key_hash" VARCHAR NOT NULL UNIQUE,
+    "expires_at" TIMESTAMP WITH TIME ZONE,
+    "last_used_at" TIMESTAMP WITH TIME ZONE,
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    "revoked_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx ON api_keys(user_id);
+
+CREATE TABLE IF NOT EXISTS "api_key_scopes"(
+    "api_key_id" INTEGER NOT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    "resource" VARCHAR NOT NULL,
+    "action" VARCHAR NOT NULL,
+    PRIMARY KEY(api_key_id, resource, action)
+);
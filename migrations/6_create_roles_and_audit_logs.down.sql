@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS audit_logs;
+
+ALTER TABLE permissions DROP CONSTRAINT IF EXISTS permissions_user_type_fkey;
+ALTER TABLE permissions ADD CONSTRAINT permissions_user_type_check CHECK ("user_type" IN('superadmin', 'user'));
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_type_fkey;
+ALTER TABLE users ADD CONSTRAINT users_type_check CHECK ("type" IN('superadmin', 'user'));
+
+DROP TABLE IF EXISTS roles;
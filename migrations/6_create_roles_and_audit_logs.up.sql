@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS "roles"(
+    "id" SERIAL PRIMARY KEY,
+    "name" VARCHAR(50) NOT NULL UNIQUE,
+    "description" VARCHAR,
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO roles(name, description) VALUES ('superadmin', 'Full access to every resource') ON CONFLICT DO NOTHING;
+INSERT INTO roles(name, description) VALUES ('user', 'Registered user') ON CONFLICT DO NOTHING;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_type_check;
+ALTER TABLE users ADD CONSTRAINT users_type_fkey
+    FOREIGN KEY ("type") REFERENCES roles(name) ON UPDATE CASCADE;
+
+ALTER TABLE permissions DROP CONSTRAINT IF EXISTS permissions_user_type_check;
+ALTER TABLE permissions ADD CONSTRAINT permissions_user_type_fkey
+    FOREIGN KEY ("user_type") REFERENCES roles(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+CREATE TABLE IF NOT EXISTS "audit_logs"(
+    "id" SERIAL PRIMARY KEY,
+    "actor_id" INTEGER,
+    "action" VARCHAR(20) NOT NULL,
+    "entity" VARCHAR(50) NOT NULL,
+    "entity_id" BIGINT NOT NULL,
+    "old_value" JSONB,
+    "new_value" JSONB,
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS audit_logs_entity_idx ON audit_logs(entity, entity_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS "user_roles"(
+    "user_id" INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "role_id" INTEGER NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    "created_at" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY(user_id, role_id)
+);
+
+-- every user keeps the role of its type
+INSERT INTO user_roles(user_id, role_id)
+SELECT u.id, r.id FROM users u JOIN roles r ON r.name = u.type
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+ALTER TABLE permissions ADD COLUMN IF NOT EXISTS "condition" VARCHAR(10) NOT NULL DEFAULT 'any'
+    CHECK ("condition" IN('any', 'own'));
+
+-- regular users may change only their own profile and posts
+UPDATE permissions SET condition='own' WHERE user_type='user' AND resource='users' AND action IN('update', 'delete');
+UPDATE permissions SET condition='own' WHERE user_type='user' AND resource='posts' AND action='update';
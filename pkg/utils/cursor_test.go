@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	type position struct {
+		CreatedAt time.Time `json:"c"`
+		ID        int64     `json:"i"`
+	}
+
+	p := position{
+		CreatedAt: time.Date(2022, 10, 5, 12, 30, 0, 123456000, time.UTC),
+		ID:        42,
+	}
+
+	cursor, err := EncodeCursor(p)
+	require.NoError(t, err)
+	require.NotEmpty(t, cursor)
+
+	var decoded position
+	err = DecodeCursor(cursor, &decoded)
+	require.NoError(t, err)
+	require.True(t, p.CreatedAt.Equal(decoded.CreatedAt))
+	require.Equal(t, p.ID, decoded.ID)
+
+	err = DecodeCursor("not a cursor", &decoded)
+	require.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -0,0 +1,212 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/TemurMannonov/medium_user_service/config"
+	"github.com/golang-jwt/jwt"
+)
+
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeySet holds the key used to sign new tokens and all keys accepted for verification.
+//
+// When cfg.AuthKeysDir is empty tokens are signed with HS256 and cfg.AuthSecretKey.
+// Otherwise every <kid>.pem file in the directory is loaded: private keys (RSA or Ed25519)
+// can sign and verify, public keys can only verify. The key named by cfg.AuthSigningKeyID
+// signs new tokens, the others stay valid for verification until they are removed,
+// which makes key rotation possible without logging users out.
+// The HS256 tokens issued before the switch to the key directory are accepted only while
+// cfg.AuthAcceptHS256Tokens is set, as the migration period.
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+// JWK is a public key in the JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the JSON Web Key Set published to the other services
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewKeySet loads the signing and verification keys described by the config
+func NewKeySet(cfg *config.Config) (*KeySet, error) {
+	ks := &KeySet{
+		keys: make(map[string]*signingKey),
+	}
+
+	var hmacKey *signingKey
+	if cfg.AuthSecretKey != "" {
+		hmacKey = &signingKey{
+			method:    jwt.SigningMethodHS256,
+			signKey:   []byte(cfg.AuthSecretKey),
+			verifyKey: []byte(cfg.AuthSecretKey),
+		}
+	}
+
+	if cfg.AuthKeysDir == "" {
+		if hmacKey == nil {
+			return nil, errors.New("auth secret key is not set")
+		}
+		ks.keys[""] = hmacKey
+		ks.active = hmacKey
+		return ks, nil
+	}
+
+	if cfg.AuthAcceptHS256Tokens {
+		if hmacKey == nil {
+			return nil, errors.New("auth secret key is not set, the HS256 tokens can't be accepted")
+		}
+		// tokens without kid header are verified with the shared secret
+		ks.keys[""] = hmacKey
+	}
+
+	files, err := filepath.Glob(filepath.Join(cfg.AuthKeysDir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		key, err := parseSigningKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %s: %w", file, err)
+		}
+
+		ks.keys[kid] = key
+	}
+
+	active, ok := ks.keys[cfg.AuthSigningKeyID]
+	if !ok || cfg.AuthSigningKeyID == "" {
+		return nil, fmt.Errorf("signing key %q is not found in %s", cfg.AuthSigningKeyID, cfg.AuthKeysDir)
+	}
+
+	if active.signKey == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", cfg.AuthSigningKeyID)
+	}
+
+	ks.active = active
+	return ks, nil
+}
+
+func parseSigningKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+
+	var (
+		private interface{}
+		public  interface{}
+		err     error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported pem block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		public = &k.PublicKey
+	case ed25519.PrivateKey:
+		public = k.Public()
+	}
+
+	key := &signingKey{
+		id:        kid,
+		signKey:   private,
+		verifyKey: public,
+	}
+
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("only RSA and Ed25519 keys are supported")
+	}
+
+	return key, nil
+}
+
+// JWKS returns the public keys that can be used to verify tokens.
+// The shared HS256 secret is never published.
+func (ks *KeySet) JWKS() JWKS {
+	result := JWKS{
+		Keys: make([]JWK, 0),
+	}
+
+	for kid, key := range ks.keys {
+		if kid == "" {
+			continue
+		}
+
+		jwk := JWK{
+			Kid: kid,
+			Use: "sig",
+			Alg: key.method.Alg(),
+		}
+
+		switch k := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(k)
+		}
+
+		result.Keys = append(result.Keys, jwk)
+	}
+
+	sort.Slice(result.Keys, func(i, j int) bool {
+		return result.Keys[i].Kid < result.Keys[j].Kid
+	})
+
+	return result
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/config"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKey(t *testing.T, dir, kid string, key interface{}) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	err = os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0600)
+	require.NoError(t, err)
+}
+
+func TestKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writeKey(t, dir, "rsa-1", rsaKey)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writeKey(t, dir, "ed-2", edKey)
+
+	oldKeys, err := NewKeySet(&config.Config{AuthKeysDir: dir, AuthSigningKeyID: "rsa-1"})
+	require.NoError(t, err)
+
+	oldToken, _, err := oldKeys.CreateToken(&TokenParams{UserID: 1, Duration: time.Minute})
+	require.NoError(t, err)
+
+	newKeys, err := NewKeySet(&config.Config{AuthKeysDir: dir, AuthSigningKeyID: "ed-2"})
+	require.NoError(t, err)
+
+	newToken, _, err := newKeys.CreateToken(&TokenParams{UserID: 2, Duration: time.Minute})
+	require.NoError(t, err)
+
+	payload, err := newKeys.VerifyToken(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), payload.UserID)
+
+	payload, err = newKeys.VerifyToken(newToken)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), payload.UserID)
+
+	jwks := newKeys.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	require.Equal(t, "EdDSA", jwks.Keys[0].Alg)
+	require.Equal(t, "RS256", jwks.Keys[1].Alg)
+
+	hmacKeys, err := NewKeySet(&config.Config{AuthSecretKey: "secret_key"})
+	require.NoError(t, err)
+
+	_, err = hmacKeys.VerifyToken(newToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestKeySetHS256Migration(t *testing.T) {
+	dir := t.TempDir()
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writeKey(t, dir, "ed-1", edKey)
+
+	hmacKeys, err := NewKeySet(&config.Config{AuthSecretKey: "secret_key"})
+	require.NoError(t, err)
+
+	hmacToken, _, err := hmacKeys.CreateToken(&TokenParams{UserID: 1, Duration: time.Minute})
+	require.NoError(t, err)
+
+	keys, err := NewKeySet(&config.Config{AuthSecretKey: "secret_key", AuthKeysDir: dir, AuthSigningKeyID: "ed-1"})
+	require.NoError(t, err)
+
+	_, err = keys.VerifyToken(hmacToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	keys, err = NewKeySet(&config.Config{
+		AuthSecretKey:         "secret_key",
+		AuthKeysDir:           dir,
+		AuthSigningKeyID:      "ed-1",
+		AuthAcceptHS256Tokens: true,
+	})
+	require.NoError(t, err)
+
+	payload, err := keys.VerifyToken(hmacToken)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), payload.UserID)
+
+	_, err = NewKeySet(&config.Config{AuthKeysDir: dir, AuthSigningKeyID: "ed-1", AuthAcceptHS256Tokens: true})
+	require.Error(t, err)
+}
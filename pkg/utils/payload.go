@@ -19,6 +19,8 @@ type Payload struct {
 	UserID    int64     `json:"user_id"`
 	Email     string    `json:"email"`
 	UserType  string    `json:"type"`
+	Roles     []string  `json:"roles,omitempty"`
+	SessionID string    `json:"session_id,omitempty"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
@@ -35,6 +37,8 @@ func NewPayload(params *TokenParams) (*Payload, error) {
 		UserID:    params.UserID,
 		Email:     params.Email,
 		UserType:  params.UserType,
+		Roles:     params.Roles,
+		SessionID: params.SessionID,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(params.Duration),
 	}
@@ -48,3 +52,13 @@ func (payload *Payload) Valid() error {
 	}
 	return nil
 }
+
+// HasRole reports whether the token carries the role, a token without roles carries none
+func (payload *Payload) HasRole(role string) bool {
+	for _, r := range payload.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
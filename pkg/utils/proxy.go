@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// TrustedProxies are the networks of the proxies whose x-forwarded-for header is believed
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies accepts ip addresses and CIDR ranges, empty values are skipped
+func ParseTrustedProxies(values []string) (TrustedProxies, error) {
+	result := make(TrustedProxies, 0, len(values))
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy: %s", value)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			result = append(result, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %s", value)
+		}
+		result = append(result, network)
+	}
+
+	return result, nil
+}
+
+func (p TrustedProxies) Contains(ip net.IP) bool {
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the ip of the peer, or the one it forwarded when the peer is a trusted proxy.
+// The forwarded chain is read from the right, so the entries a client prepends itself are ignored.
+func (p TrustedProxies) ClientIP(peerIP, forwardedFor string) string {
+	ip := net.ParseIP(peerIP)
+	if ip == nil || !p.Contains(ip) || forwardedFor == "" {
+		return peerIP
+	}
+
+	hops := strings.Split(forwardedFor, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			// whatever is left of a malformed entry can't be trusted
+			return peerIP
+		}
+
+		if i == 0 || !p.Contains(hop) {
+			return hop.String()
+		}
+	}
+
+	return peerIP
+}
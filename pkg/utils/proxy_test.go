@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxies(t *testing.T) {
+	_, err := ParseTrustedProxies([]string{"not an ip"})
+	require.Error(t, err)
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", " 192.168.1.1", "", "::1"})
+	require.NoError(t, err)
+	require.Len(t, proxies, 3)
+
+	// the header of an untrusted peer is ignored
+	require.Equal(t, "203.0.113.7", proxies.ClientIP("203.0.113.7", "198.51.100.1"))
+
+	// a trusted peer without the header is the client
+	require.Equal(t, "10.0.0.1", proxies.ClientIP("10.0.0.1", ""))
+
+	require.Equal(t, "198.51.100.1", proxies.ClientIP("10.0.0.1", "198.51.100.1"))
+	require.Equal(t, "198.51.100.1", proxies.ClientIP("::1", "198.51.100.1, 192.168.1.1"))
+
+	// the entries prepended by the client are skipped
+	require.Equal(t, "198.51.100.1", proxies.ClientIP("10.0.0.1", "1.2.3.4, 198.51.100.1"))
+
+	// a chain of trusted proxies ends with its first entry
+	require.Equal(t, "10.0.0.3", proxies.ClientIP("10.0.0.1", "10.0.0.3, 10.0.0.2"))
+
+	require.Equal(t, "10.0.0.1", proxies.ClientIP("10.0.0.1", "1.2.3.4, garbage"))
+
+	var none TrustedProxies
+	require.Equal(t, "10.0.0.1", none.ClientIP("10.0.0.1", "198.51.100.1"))
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"encoding/base64"
 	"io"
 )
 
@@ -20,3 +21,14 @@ func GenerateRandomCode(max int) (string, error) {
 
 	return string(b), nil
 }
+
+// GenerateRandomToken returns a URL-safe random string built from size random bytes
+func GenerateRandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	_, err := io.ReadFull(rand.Reader, b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -1,41 +1,57 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"time"
 
-	"github.com/TemurMannonov/medium_user_service/config"
 	"github.com/golang-jwt/jwt"
 )
 
 type TokenParams struct {
-	UserID   int64
-	Username string
-	Email    string
-	UserType string
-	Duration time.Duration
+	UserID    int64
+	Username  string
+	Email     string
+	UserType  string
+	Roles     []string
+	SessionID string
+	Duration  time.Duration
 }
 
-// CreateToken creates a new token
-func CreateToken(cfg *config.Config, params *TokenParams) (string, *Payload, error) {
+// CreateToken creates a new token signed with the active key of the key set
+func (ks *KeySet) CreateToken(params *TokenParams) (string, *Payload, error) {
 	payload, err := NewPayload(params)
 	if err != nil {
 		return "", payload, err
 	}
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
-	token, err := jwtToken.SignedString([]byte(cfg.AuthSecretKey))
+	jwtToken := jwt.NewWithClaims(ks.active.method, payload)
+	if ks.active.id != "" {
+		jwtToken.Header["kid"] = ks.active.id
+	}
+
+	token, err := jwtToken.SignedString(ks.active.signKey)
 	return token, payload, err
 }
 
 // VerifyToken checks if the token is valid or not
-func VerifyToken(cfg *config.Config, token string) (*Payload, error) {
+func (ks *KeySet) VerifyToken(token string) (*Payload, error) {
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
-		_, ok := token.Method.(*jwt.SigningMethodHMAC)
+		kid, _ := token.Header["kid"].(string)
+
+		key, ok := ks.keys[kid]
 		if !ok {
 			return nil, ErrInvalidToken
 		}
-		return []byte(cfg.AuthSecretKey), nil
+
+		// the algorithm of the token must match the key, otherwise
+		// a public key could be used as an HMAC secret
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, ErrInvalidToken
+		}
+
+		return key.verifyKey, nil
 	}
 
 	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
@@ -54,3 +70,10 @@ func VerifyToken(cfg *config.Config, token string) (*Payload, error) {
 
 	return payload, nil
 }
+
+// HashToken returns the hex encoded SHA-256 hash of an opaque token,
+// so that tokens are never stored in plain text
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
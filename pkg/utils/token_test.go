@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToken(t *testing.T) {
+	keys, err := NewKeySet(&config.Config{AuthSecretKey: "secret_key"})
+	require.NoError(t, err)
+
+	token, payload, err := keys.CreateToken(&TokenParams{
+		UserID:   1,
+		Email:    "user@gmail.com",
+		UserType: "user",
+		Duration: time.Minute,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	result, err := keys.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, payload.ID, result.ID)
+	require.Equal(t, payload.UserID, result.UserID)
+
+	otherKeys, err := NewKeySet(&config.Config{AuthSecretKey: "other_key"})
+	require.NoError(t, err)
+
+	_, err = otherKeys.VerifyToken(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	expiredToken, _, err := keys.CreateToken(&TokenParams{
+		UserID:   1,
+		Duration: -time.Minute,
+	})
+	require.NoError(t, err)
+
+	_, err = keys.VerifyToken(expiredToken)
+	require.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestRandomToken(t *testing.T) {
+	token1, err := GenerateRandomToken(32)
+	require.NoError(t, err)
+	require.NotEmpty(t, token1)
+
+	token2, err := GenerateRandomToken(32)
+	require.NoError(t, err)
+	require.NotEqual(t, token1, token2)
+
+	require.Equal(t, HashToken(token1), HashToken(token1))
+	require.NotEqual(t, HashToken(token1), HashToken(token2))
+}
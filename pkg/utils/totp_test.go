@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTP(t *testing.T) {
+	// Test vectors from RFC 6238 appendix B, truncated to 6 digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range cases {
+		code, err := GenerateTOTPCode(secret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, expected, code)
+	}
+
+	require.True(t, ValidateTOTPCode(secret, "287082", time.Unix(59+30, 0)))
+	require.False(t, ValidateTOTPCode(secret, "287082", time.Unix(59+90, 0)))
+
+	newSecret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	code, err := GenerateTOTPCode(newSecret, time.Now())
+	require.NoError(t, err)
+	require.True(t, ValidateTOTPCode(newSecret, code, time.Now()))
+}
@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"type":"user.created","user_id":1}`)
+
+	received := make(chan *http.Request, 1)
+	var receivedBody []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedBody, _ = io.ReadAll(r.Body)
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := newClient(time.Second, func(net.IP) bool { return true })
+
+	statusCode, err := client.Send(context.Background(), &Request{
+		URL:        server.URL,
+		Secret:     secret,
+		Event:      "user.created",
+		DeliveryID: "1",
+		Body:       body,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, statusCode)
+
+	r := <-received
+	require.Equal(t, http.MethodPost, r.Method)
+	require.Equal(t, "user.created", r.Header.Get(EventHeader))
+	require.Equal(t, "1", r.Header.Get(DeliveryHeader))
+	require.Equal(t, body, receivedBody)
+
+	timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+	require.NoError(t, err)
+
+	signature := r.Header.Get(SignatureHeader)
+	require.True(t, Verify(secret, signature, timestamp, receivedBody))
+	require.False(t, Verify("other_secret", signature, timestamp, receivedBody))
+	require.False(t, Verify(secret, signature, timestamp+1, receivedBody))
+	require.False(t, Verify(secret, signature, timestamp, []byte(`{"type":"user.deleted","user_id":1}`)))
+}
+
+func TestSendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+
+	client := newClient(time.Second, func(net.IP) bool { return true })
+	req := &Request{
+		URL:    server.URL,
+		Secret: "whsec_test",
+		Body:   []byte(`{}`),
+	}
+
+	statusCode, err := client.Send(context.Background(), req)
+	require.Equal(t, http.StatusServiceUnavailable, statusCode)
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+
+	redirect := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusFound))
+	defer redirect.Close()
+
+	req.URL = redirect.URL
+	statusCode, err = client.Send(context.Background(), req)
+	require.Error(t, err)
+	require.Equal(t, http.StatusFound, statusCode)
+
+	server.Close()
+
+	req.URL = server.URL
+	statusCode, err = client.Send(context.Background(), req)
+	require.Error(t, err)
+	require.Zero(t, statusCode)
+}
+
+func TestSendForbiddenAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second)
+
+	statusCode, err := client.Send(context.Background(), &Request{
+		URL:    server.URL,
+		Secret: "whsec_test",
+		Body:   []byte(`{}`),
+	})
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+	require.Zero(t, statusCode)
+
+	require.ErrorIs(t, ValidateURL(context.Background(), server.URL), ErrForbiddenAddress)
+	require.ErrorIs(t, ValidateURL(context.Background(), "http://localhost:8080/hook"), ErrForbiddenAddress)
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "0.0.0.0", "::1", "fe80::1", "fc00::1", "::ffff:127.0.0.1"} {
+		require.False(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+
+	for _, ip := range []string{"8.8.8.8", "2001:4860:4860::8888"} {
+		require.True(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	ApiKeyPrefix = "pat_"
+
+	TokenTypeAccessToken = "access_token"
+	TokenTypeApiKey      = "api_key"
+
+	apiKeyByteLength    = 32
+	apiKeyDisplayLength = 12
+)
+
+func (s *AuthService) CreateApiKey(ctx context.Context, req *pb.CreateApiKeyRequest) (*pb.CreateApiKeyResponse, error) {
+	payload, err := s.verifyAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name is required")
+	}
+
+	if len(req.Scopes) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "at least one scope is required")
+	}
+
+	var expiresAt time.Time
+	if req.ExpiresAt != "" {
+		expiresAt, err = time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid expires_at: %v", err)
+		}
+
+		if expiresAt.Before(time.Now()) {
+			return nil, status.Errorf(codes.InvalidArgument, "expires_at must be in the future")
+		}
+	}
+
+	// a key can't be granted more than its owner is allowed to do, a repeated scope is granted once
+	scopes := make([]*repo.ApiKeyScope, 0, len(req.Scopes))
+	seen := make(map[repo.ApiKeyScope]bool, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		granted := repo.ApiKeyScope{Resource: scope.Resource, Action: scope.Action}
+		if seen[granted] {
+			continue
+		}
+		seen[granted] = true
+
+		hasPermission, err := s.storage.Permission().CheckPermission(payload.Roles, scope.Resource, scope.Action)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		}
+
+		if !hasPermission {
+			return nil, status.Errorf(codes.PermissionDenied, "no permission for %s:%s", scope.Resource, scope.Action)
+		}
+
+		scopes = append(scopes, &granted)
+	}
+
+	random, err := utils.GenerateRandomToken(apiKeyByteLength)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate api key: %v", err)
+	}
+	key := ApiKeyPrefix + random
+
+	apiKey, err := s.storage.ApiKey().Create(&repo.ApiKey{
+		UserID:    payload.UserID,
+		Name:      req.Name,
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   utils.HashToken(key),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to create api key")
+		return nil, status.Errorf(codes.Internal, "failed to create api key: %v", err)
+	}
+
+	return &pb.CreateApiKeyResponse{
+		ApiKey: parseApiKeyModel(apiKey),
+		Key:    key,
+	}, nil
+}
+
+func (s *AuthService) ListApiKeys(ctx context.Context, req *pb.ListApiKeysRequest) (*pb.ListApiKeysResponse, error) {
+	payload, err := s.verifyAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys, err := s.storage.ApiKey().GetAllByUser(payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get api keys")
+		return nil, status.Errorf(codes.Internal, "failed to get api keys: %v", err)
+	}
+
+	response := pb.ListApiKeysResponse{
+		ApiKeys: make([]*pb.ApiKey, 0),
+	}
+
+	for _, apiKey := range apiKeys {
+		response.ApiKeys = append(response.ApiKeys, parseApiKeyModel(apiKey))
+	}
+
+	return &response, nil
+}
+
+func (s *AuthService) RevokeApiKey(ctx context.Context, req *pb.RevokeApiKeyRequest) (*emptypb.Empty, error) {
+	payload, err := s.verifyAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.ApiKey().Revoke(req.Id, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to revoke api key")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "api key not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to revoke api key: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// verifyApiKey resolves the owner of the key. The permission is decided by checkPermission,
+// which limits the key's scopes by what the owner is allowed to do.
+func (s *AuthService) verifyApiKey(key string) (*pb.AuthPayload, *repo.ApiKey, error) {
+	apiKey, err := s.storage.ApiKey().GetByHash(utils.HashToken(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", utils.ErrInvalidToken)
+		}
+		s.logger.WithError(err).Error("failed to get api key")
+		return nil, nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	user, err := s.storage.User().Get(apiKey.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", utils.ErrInvalidToken)
+		}
+		return nil, nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	err = checkUserStatus(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roles, err := s.getUserRoles(user.ID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user roles")
+		return nil, nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	err = s.storage.ApiKey().UpdateLastUsed(apiKey.ID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to update api key last used time")
+	}
+
+	var expiredAt string
+	if !apiKey.ExpiresAt.IsZero() {
+		expiredAt = apiKey.ExpiresAt.Format(time.RFC3339)
+	}
+
+	return &pb.AuthPayload{
+		Id:        strconv.FormatInt(apiKey.ID, 10),
+		UserId:    user.ID,
+		Email:     user.Email,
+		UserType:  user.Type,
+		IssuedAt:  apiKey.CreatedAt.Format(time.RFC3339),
+		ExpiredAt: expiredAt,
+		TokenType: TokenTypeApiKey,
+		Roles:     roles,
+	}, apiKey, nil
+}
+
+func parseApiKeyModel(apiKey *repo.ApiKey) *pb.ApiKey {
+	result := pb.ApiKey{
+		Id:        apiKey.ID,
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		Scopes:    make([]*pb.ApiKeyScope, 0, len(apiKey.Scopes)),
+		CreatedAt: apiKey.CreatedAt.Format(time.RFC3339),
+	}
+
+	if !apiKey.ExpiresAt.IsZero() {
+		result.ExpiresAt = apiKey.ExpiresAt.Format(time.RFC3339)
+	}
+
+	if !apiKey.LastUsedAt.IsZero() {
+		result.LastUsedAt = apiKey.LastUsedAt.Format(time.RFC3339)
+	}
+
+	for _, scope := range apiKey.Scopes {
+		result.Scopes = append(result.Scopes, &pb.ApiKeyScope{
+			Resource: scope.Resource,
+			Action:   scope.Action,
+		})
+	}
+
+	return &result
+}
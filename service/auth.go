@@ -6,10 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/TemurMannonov/medium_user_service/config"
-	"github.com/TemurMannonov/medium_user_service/genproto/notification_service"
 	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
 	"github.com/TemurMannonov/medium_user_service/pkg/utils"
 	"github.com/TemurMannonov/medium_user_service/storage/repo"
@@ -24,26 +24,37 @@ import (
 
 type AuthService struct {
 	pb.UnimplementedAuthServiceServer
-	storage    storage.StorageI
-	inMemory   storage.InMemoryStorageI
-	grpcClient grpcPkg.GrpcClientI
-	cfg        *config.Config
-	logger     *logrus.Logger
+	storage        storage.StorageI
+	inMemory       storage.InMemoryStorageI
+	grpcClient     grpcPkg.GrpcClientI
+	keys           *utils.KeySet
+	trustedProxies utils.TrustedProxies
+	cfg            *config.Config
+	logger         *logrus.Logger
 }
 
-func NewAuthService(strg storage.StorageI, inMemory storage.InMemoryStorageI, grpcConn grpcPkg.GrpcClientI, cfg *config.Config, logger *logrus.Logger) *AuthService {
+func NewAuthService(strg storage.StorageI, inMemory storage.InMemoryStorageI, grpcConn grpcPkg.GrpcClientI, keys *utils.KeySet, trustedProxies utils.TrustedProxies, cfg *config.Config, logger *logrus.Logger) *AuthService {
 	return &AuthService{
-		storage:    strg,
-		inMemory:   inMemory,
-		grpcClient: grpcConn,
-		cfg:        cfg,
-		logger:     logger,
+		storage:        strg,
+		inMemory:       inMemory,
+		grpcClient:     grpcConn,
+		keys:           keys,
+		trustedProxies: trustedProxies,
+		cfg:            cfg,
+		logger:         logger,
 	}
 }
 
 const (
-	RegisterCodeKey   = "register_code_"
-	ForgotPasswordKey = "forgot_password_code_"
+	RegisterCodeKey     = "register_code_"
+	ForgotPasswordKey   = "forgot_password_code_"
+	TokensValidAfterKey = "tokens_valid_after_"
+	RevokedTokenKey     = "revoked_token_"
+	RolesChangedAtKey   = "roles_changed_at_"
+
+	// VerificationCodeTTL covers the retries of the outbox, the emails still undelivered
+	// once it passes are dead-lettered
+	VerificationCodeTTL = 10 * time.Minute
 )
 
 func (s *AuthService) Register(ctx context.Context, req *pb.RegisterRequest) (*emptypb.Empty, error) {
@@ -70,34 +81,37 @@ func (s *AuthService) Register(ctx context.Context, req *pb.RegisterRequest) (*e
 		return nil, status.Errorf(codes.Internal, "failed to set to rd: %v", err)
 	}
 
-	go func() {
-		err := s.sendVerificationCode(RegisterCodeKey, req.Email)
-		if err != nil {
-			fmt.Printf("failed to send verification code: %v", err)
-		}
-	}()
+	err = s.sendVerificationCode(repo.EventUserVerificationRequested, RegisterCodeKey, req.Email, 0)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to send verification code")
+		return nil, status.Errorf(codes.Internal, "failed to send verification code: %v", err)
+	}
 
 	return &emptypb.Empty{}, nil
 }
 
-func (s *AuthService) sendVerificationCode(key, email string) error {
+// sendVerificationCode stores a new code and queues the event emailing it in the outbox,
+// the dispatcher keeps retrying while the notification service is down
+func (s *AuthService) sendVerificationCode(eventType, key, email string, userID int64) error {
 	code, err := utils.GenerateRandomCode(6)
 	if err != nil {
 		return err
 	}
 
-	err = s.inMemory.Set(key+email, code, time.Minute)
+	err = s.inMemory.Set(key+email, code, VerificationCodeTTL)
 	if err != nil {
 		return err
 	}
 
-	_, err = s.grpcClient.NotificationService().SendEmail(context.Background(), &notification_service.SendEmailRequest{
-		To:      email,
-		Subject: "Verification email",
-		Body: map[string]string{
-			"code": code,
-		},
-		Type: "verification_email",
+	err = s.inMemory.Del(CodeAttemptsKey + key + email)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.storage.Outbox().Create(eventType, userID, &repo.VerificationCodePayload{
+		UserID: userID,
+		Email:  email,
+		Code:   code,
 	})
 	if err != nil {
 		return err
@@ -107,6 +121,11 @@ func (s *AuthService) sendVerificationCode(key, email string) error {
 }
 
 func (s *AuthService) Verify(ctx context.Context, req *pb.VerifyRegisterRequest) (*pb.AuthResponse, error) {
+	err := s.checkLockout(ctx, attemptScopeVerify, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
 	userData, err := s.inMemory.Get("user_" + req.Email)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, err.Error())
@@ -124,64 +143,53 @@ func (s *AuthService) Verify(ctx context.Context, req *pb.VerifyRegisterRequest)
 	}
 
 	if req.Code != code {
-		return nil, status.Errorf(codes.Internal, "incorrect_code")
+		s.registerFailedAttempt(ctx, attemptScopeVerify, req.Email)
+		return nil, s.registerWrongCode(RegisterCodeKey+user.Email, status.Errorf(codes.Internal, "incorrect_code"))
 	}
 
+	s.resetFailedAttempts(attemptScopeVerify, req.Email)
+
 	result, err := s.storage.User().Create(&user)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
+		s.logger.WithError(err).Error("failed to create user")
+		return nil, createUserError(err)
 	}
 
-	token, _, err := utils.CreateToken(s.cfg, &utils.TokenParams{
-		UserID:   result.ID,
-		Email:    result.Email,
-		UserType: result.Type,
-		Duration: time.Hour * 24,
-	})
+	response, err := s.createAuthResponse(ctx, result, "")
 	if err != nil {
+		s.logger.WithError(err).Error("failed to create token")
 		return nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
 	}
 
-	return &pb.AuthResponse{
-		Id:          result.ID,
-		FirstName:   result.FirstName,
-		LastName:    result.LastName,
-		Email:       result.Email,
-		Type:        result.Type,
-		CreatedAt:   result.CreatedAt.Format(time.RFC3339),
-		AccessToken: token,
-	}, nil
+	return response, nil
 }
 
 func (s *AuthService) VerifyToken(ctx context.Context, req *pb.VerifyTokenRequest) (*pb.AuthPayload, error) {
-	accessToken := req.AccessToken
-
-	payload, err := utils.VerifyToken(s.cfg, accessToken)
+	payload, apiKey, err := s.authenticate(req.AccessToken)
 	if err != nil {
-		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+		return nil, err
 	}
 
-	hasPermission, err := s.storage.Permission().CheckPermission(payload.UserType, req.Resource, req.Action)
+	payload.Condition, payload.HasPermission, err = s.checkPermission(payload, apiKey, req.Resource, req.Action, req.ResourceOwnerId)
 	if err != nil {
+		s.logger.WithError(err).Error("failed to check permission")
 		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
 	}
 
-	return &pb.AuthPayload{
-		Id:            payload.ID.String(),
-		UserId:        payload.UserID,
-		Email:         payload.Email,
-		UserType:      payload.UserType,
-		IssuedAt:      payload.IssuedAt.Format(time.RFC3339),
-		ExpiredAt:     payload.ExpiredAt.Format(time.RFC3339),
-		HasPermission: hasPermission,
-	}, nil
+	return payload, nil
 }
 
 func (s *AuthService) Login(ctx context.Context, req *pb.LoginRequest) (*pb.AuthResponse, error) {
+	err := s.checkLockout(ctx, attemptScopeLogin, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.storage.User().GetByEmail(req.Email)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to get user by email")
 		if errors.Is(err, sql.ErrNoRows) {
+			s.registerFailedAttempt(ctx, attemptScopeLogin, req.Email)
 			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
 		}
 		return nil, status.Errorf(codes.Internal, "internal server error: %v", err)
@@ -189,27 +197,399 @@ func (s *AuthService) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Auth
 
 	err = utils.CheckPassword(req.Password, user.Password)
 	if err != nil {
+		s.registerFailedAttempt(ctx, attemptScopeLogin, req.Email)
 		return nil, status.Errorf(codes.Internal, "incorrect_password")
 	}
 
-	token, _, err := utils.CreateToken(s.cfg, &utils.TokenParams{
+	s.resetFailedAttempts(attemptScopeLogin, req.Email)
+
+	err = checkUserStatus(user)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := s.storage.TwoFactor().Get(user.ID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get two factor settings")
+		return nil, status.Errorf(codes.Internal, "internal server error: %v", err)
+	}
+
+	if twoFactor.Enabled {
+		// no new challenge while the two factor step is locked out
+		err = s.checkLockout(ctx, attemptScopeTwoFactor, strconv.FormatInt(user.ID, 10))
+		if err != nil {
+			return nil, err
+		}
+
+		challengeToken, err := s.createTwoFactorChallenge(user.ID)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to create two factor challenge")
+			return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		}
+
+		return &pb.AuthResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, nil
+	}
+
+	response, err := s.createAuthResponse(ctx, user, "")
+	if err != nil {
+		s.logger.WithError(err).Error("failed to create token")
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	return response, nil
+}
+
+func (s *AuthService) GetJWKS(ctx context.Context, req *emptypb.Empty) (*pb.JWKSResponse, error) {
+	jwks := s.keys.JWKS()
+
+	response := pb.JWKSResponse{
+		Keys: make([]*pb.JWK, 0, len(jwks.Keys)),
+	}
+
+	for _, key := range jwks.Keys {
+		response.Keys = append(response.Keys, &pb.JWK{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+
+	return &response, nil
+}
+
+func (s *AuthService) ForgotPassword(ctx context.Context, req *pb.ForgotPasswordRequest) (*emptypb.Empty, error) {
+	user, err := s.storage.User().GetByEmail(req.Email)
+	if err != nil {
+		// the response is the same for unknown emails, so it doesn't reveal who is registered
+		if errors.Is(err, sql.ErrNoRows) {
+			return &emptypb.Empty{}, nil
+		}
+		s.logger.WithError(err).Error("failed to get user by email")
+		return nil, status.Errorf(codes.Internal, "internal server error: %v", err)
+	}
+
+	err = s.sendVerificationCode(repo.EventPasswordResetRequested, ForgotPasswordKey, req.Email, user.ID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to send forgot password code")
+		return nil, status.Errorf(codes.Internal, "failed to send forgot password code: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *AuthService) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*emptypb.Empty, error) {
+	err := s.checkLockout(ctx, attemptScopeReset, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.inMemory.Get(ForgotPasswordKey + req.Email)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "code_expired")
+	}
+
+	if req.Code != code {
+		s.registerFailedAttempt(ctx, attemptScopeReset, req.Email)
+		return nil, s.registerWrongCode(ForgotPasswordKey+req.Email, status.Errorf(codes.Internal, "incorrect_code"))
+	}
+
+	s.resetFailedAttempts(attemptScopeReset, req.Email)
+
+	user, err := s.storage.User().GetByEmail(req.Email)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user by email")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "internal server error: %v", err)
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to hash: %v", err)
+	}
+
+	err = s.storage.User().UpdatePassword(&repo.UpdatePassword{
 		UserID:   user.ID,
-		Email:    user.Email,
-		UserType: user.Type,
-		Duration: time.Hour * 24,
+		Password: hashedPassword,
 	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to update password")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to update password: %v", err)
+	}
+
+	err = s.inMemory.Del(ForgotPasswordKey + req.Email)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to delete forgot password code")
+	}
+
+	err = s.revokeUserCredentials(user.ID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to revoke user credentials")
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *AuthService) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.AuthResponse, error) {
+	tokenHash := utils.HashToken(req.RefreshToken)
+
+	tokenData, err := s.inMemory.Get(RefreshTokenKey + tokenHash)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token")
+		}
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	var data refreshTokenData
+	err = json.Unmarshal([]byte(tokenData), &data)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to unmarshal: %v", err)
+	}
+
+	// Every refresh token can be exchanged only once. If it is presented again
+	// it has most likely been stolen, so the whole family is revoked.
+	firstUse, err := s.inMemory.SetNX(RefreshTokenUsedKey+tokenHash, "1", s.cfg.RefreshTokenDuration)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	if !firstUse {
+		s.logger.WithField("user_id", data.UserID).Warn("refresh token reuse detected")
+		err = s.revokeSession(data.SessionID)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to revoke session")
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "refresh token reuse detected")
+	}
+
+	sessionRevoked, err := s.isSessionRevoked(data.SessionID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	revoked, err := s.isTokenRevoked(data.UserID, data.IssuedAt)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	if sessionRevoked || revoked {
+		return nil, status.Errorf(codes.Unauthenticated, "refresh token has been revoked")
+	}
+
+	user, err := s.storage.User().Get(data.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.Unauthenticated, "user not found")
+		}
+		return nil, status.Errorf(codes.Internal, "internal server error: %v", err)
+	}
+
+	err = checkUserStatus(user)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.createAuthResponse(ctx, user, data.SessionID)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to create token")
 		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
 	}
 
-	return &pb.AuthResponse{
-		Id:          user.ID,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		Email:       user.Email,
-		Type:        user.Type,
-		CreatedAt:   user.CreatedAt.Format(time.RFC3339),
-		AccessToken: token,
-	}, nil
+	return response, nil
+}
+
+func (s *AuthService) Logout(ctx context.Context, req *pb.LogoutRequest) (*emptypb.Empty, error) {
+	payload, err := s.verifyAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.revokeAccessToken(payload)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to revoke access token")
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	sessionID := payload.SessionID
+	if sessionID == "" && req.RefreshToken != "" {
+		tokenData, err := s.inMemory.Get(RefreshTokenKey + utils.HashToken(req.RefreshToken))
+		if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+			return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		}
+
+		if err == nil {
+			var data refreshTokenData
+			err = json.Unmarshal([]byte(tokenData), &data)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to unmarshal: %v", err)
+			}
+
+			if data.UserID != payload.UserID {
+				return nil, status.Errorf(codes.PermissionDenied, "refresh token belongs to another user")
+			}
+
+			sessionID = data.SessionID
+		}
+	}
+
+	if sessionID != "" {
+		err = s.revokeSession(sessionID)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to revoke session")
+			return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		}
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *AuthService) LogoutAll(ctx context.Context, req *pb.LogoutAllRequest) (*emptypb.Empty, error) {
+	payload, err := s.verifyAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.revokeUserCredentials(payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to revoke user credentials")
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// verifyAccessToken checks the signature, expiration and revocation state of the access token
+func (s *AuthService) verifyAccessToken(accessToken string) (*utils.Payload, error) {
+	payload, err := s.keys.VerifyToken(accessToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+
+	_, err = s.inMemory.Get(RevokedTokenKey + payload.ID.String())
+	if err == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: token has been revoked")
+	}
+	if !errors.Is(err, storage.ErrKeyNotFound) {
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	revoked, err := s.isTokenRevoked(payload.UserID, payload.IssuedAt)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	if !revoked && payload.SessionID != "" {
+		revoked, err = s.isSessionRevoked(payload.SessionID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		}
+	}
+
+	if revoked {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: token has been revoked")
+	}
+
+	// the roles carried by a token issued before they changed are replaced by the current ones
+	stale, err := s.issuedBefore(fmt.Sprintf("%s%d", RolesChangedAtKey, payload.UserID), payload.IssuedAt)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	if stale {
+		payload.Roles, err = s.getUserRoles(payload.UserID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		}
+	}
+
+	return payload, nil
+}
+
+// revokeAccessToken puts the token id to the revocation list until the token expires
+func (s *AuthService) revokeAccessToken(payload *utils.Payload) error {
+	ttl := time.Until(payload.ExpiredAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return s.inMemory.Set(RevokedTokenKey+payload.ID.String(), "1", ttl)
+}
+
+// revokeUserTokens invalidates every access and refresh token issued to the user before now
+// and marks all of the user's sessions as revoked
+func (s *AuthService) revokeUserTokens(userID int64) error {
+	err := s.inMemory.Set(
+		fmt.Sprintf("%s%d", TokensValidAfterKey, userID),
+		strconv.FormatInt(time.Now().UnixNano(), 10),
+		s.cfg.RefreshTokenDuration,
+	)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.Session().RevokeAllByUser(userID)
+}
+
+// revokeUserCredentials revokes the tokens and the api keys of the user. The api keys outlive
+// the tokens, so they are revoked for good rather than by the issue time.
+func (s *AuthService) revokeUserCredentials(userID int64) error {
+	err := s.revokeUserTokens(userID)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.ApiKey().RevokeAllByUser(userID)
+}
+
+// isTokenRevoked reports whether a token issued at issuedAt was issued before the user's tokens were revoked
+func (s *AuthService) isTokenRevoked(userID int64, issuedAt time.Time) (bool, error) {
+	return s.issuedBefore(fmt.Sprintf("%s%d", TokensValidAfterKey, userID), issuedAt)
+}
+
+// invalidateUserRoles makes the access tokens issued to the users before now carry their
+// current roles. The refreshed tokens load the roles anyway, so the mark outlives the
+// access tokens only.
+func (s *AuthService) invalidateUserRoles(userIDs ...int64) error {
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	for _, userID := range userIDs {
+		err := s.inMemory.Set(fmt.Sprintf("%s%d", RolesChangedAtKey, userID), now, s.cfg.AccessTokenDuration)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// issuedBefore reports whether issuedAt is before the time stored at the key
+func (s *AuthService) issuedBefore(key string, issuedAt time.Time) (bool, error) {
+	value, err := s.inMemory.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	nano, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, err
+	}
+
+	return issuedAt.UnixNano() < nano, nil
 }
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Block makes the caller block the user and removes the follows between them
+func (s *UserService) Block(ctx context.Context, req *pb.IdRequest) (*emptypb.Empty, error) {
+	caller, err := s.requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if caller.UserId == req.Id {
+		return nil, status.Errorf(codes.InvalidArgument, "users can't block themselves")
+	}
+
+	_, err = s.storage.User().Get(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		return nil, databaseError(err, "failed to get user")
+	}
+
+	_, err = s.storage.Block().Block(caller.UserId, req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to block user")
+		return nil, databaseError(err, "failed to block user")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserService) Unblock(ctx context.Context, req *pb.IdRequest) (*emptypb.Empty, error) {
+	caller, err := s.requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.storage.Block().Unblock(caller.UserId, req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to unblock user")
+		return nil, status.Errorf(codes.Internal, "failed to unblock user: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListBlocked lists the users blocked by the caller
+func (s *UserService) ListBlocked(ctx context.Context, req *pb.ListRelatedUsersRequest) (*pb.ListRelatedUsersResponse, error) {
+	caller, err := s.requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users, nextCursor, err := s.listRelatedUsers(ctx, caller.UserId, req.Limit, req.Cursor, s.storage.Block().GetBlocked)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListRelatedUsersResponse{
+		Users:      users,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func (s *UserService) Mute(ctx context.Context, req *pb.IdRequest) (*emptypb.Empty, error) {
+	caller, err := s.requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if caller.UserId == req.Id {
+		return nil, status.Errorf(codes.InvalidArgument, "users can't mute themselves")
+	}
+
+	_, err = s.storage.User().Get(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		return nil, databaseError(err, "failed to get user")
+	}
+
+	_, err = s.storage.Block().Mute(caller.UserId, req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to mute user")
+		return nil, databaseError(err, "failed to mute user")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserService) Unmute(ctx context.Context, req *pb.IdRequest) (*emptypb.Empty, error) {
+	caller, err := s.requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.storage.Block().Unmute(caller.UserId, req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to unmute user")
+		return nil, status.Errorf(codes.Internal, "failed to unmute user: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListMuted lists the users muted by the caller
+func (s *UserService) ListMuted(ctx context.Context, req *pb.ListRelatedUsersRequest) (*pb.ListRelatedUsersResponse, error) {
+	caller, err := s.requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users, nextCursor, err := s.listRelatedUsers(ctx, caller.UserId, req.Limit, req.Cursor, s.storage.Block().GetMuted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListRelatedUsersResponse{
+		Users:      users,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// CheckInteraction tells other services whether the actor may interact with the target,
+// e.g. comment on the target's posts. Blocks and mutes are private, so only internal callers get them.
+func (s *UserService) CheckInteraction(ctx context.Context, req *pb.CheckInteractionRequest) (*pb.CheckInteractionResponse, error) {
+	if !isInternalCall(ctx, s.cfg.InternalServiceToken) {
+		return nil, status.Errorf(codes.PermissionDenied, "internal callers only")
+	}
+
+	if req.ActorId == 0 || req.TargetId == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "actor_id and target_id are required")
+	}
+
+	interaction, err := s.storage.Block().GetInteraction(req.ActorId, req.TargetId)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to check interaction")
+		return nil, status.Errorf(codes.Internal, "failed to check interaction: %v", err)
+	}
+
+	return &pb.CheckInteractionResponse{
+		CanInteract: !interaction.Blocking && !interaction.BlockedBy,
+		Blocking:    interaction.Blocking,
+		BlockedBy:   interaction.BlockedBy,
+		Muting:      interaction.Muting,
+		MutedBy:     interaction.MutedBy,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/storage"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	FailedAttemptsKey = "failed_attempts_"
+	LockoutKey        = "lockout_"
+	CodeAttemptsKey   = "code_attempts_"
+
+	attemptScopeLogin  = "login"
+	attemptScopeVerify = "verify"
+	attemptScopeReset  = "reset"
+	// the two factor step is counted per user, as its codes are guessed after the password check
+	attemptScopeTwoFactor = "two_factor"
+
+	// failures are counted within the window starting from the first failure
+	failedAttemptsWindow = 24 * time.Hour
+	maxAccountAttempts   = 5
+	maxIPAttempts        = 20
+	baseLockout          = 30 * time.Second
+	maxLockout           = time.Hour
+
+	// a verification code is invalidated after this many wrong guesses
+	maxCodeAttempts = 5
+)
+
+// checkLockout returns codes.ResourceExhausted if the account or the caller's ip is locked out.
+// The account is the email, or the user id in the two factor scope.
+func (s *AuthService) checkLockout(ctx context.Context, scope, account string) error {
+	_, ip := clientInfoFromContext(ctx, s.trustedProxies)
+
+	for _, counter := range attemptCounters(scope, account, ip) {
+		ttl, err := s.inMemory.TTL(LockoutKey + counter.key)
+		if err != nil {
+			if errors.Is(err, storage.ErrKeyNotFound) {
+				continue
+			}
+			return status.Errorf(codes.Internal, "internal error: %v", err)
+		}
+
+		return retryAfterError(ttl)
+	}
+
+	return nil
+}
+
+// registerFailedAttempt counts the failure for the account and the caller's ip.
+// Once a limit is reached the key is locked, and every further failure doubles the lockout.
+func (s *AuthService) registerFailedAttempt(ctx context.Context, scope, account string) {
+	_, ip := clientInfoFromContext(ctx, s.trustedProxies)
+
+	for _, counter := range attemptCounters(scope, account, ip) {
+		count, err := s.inMemory.Incr(FailedAttemptsKey+counter.key, failedAttemptsWindow)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to count failed attempt")
+			continue
+		}
+
+		if count < counter.limit {
+			continue
+		}
+
+		lockout := maxLockout
+		if shift := count - counter.limit; shift < 16 {
+			lockout = baseLockout << shift
+			if lockout > maxLockout {
+				lockout = maxLockout
+			}
+		}
+
+		s.logger.WithField("key", counter.key).WithField("attempts", count).Warn("too many failed attempts")
+
+		err = s.inMemory.Set(LockoutKey+counter.key, "1", lockout)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to set lockout")
+		}
+	}
+}
+
+// resetFailedAttempts clears the account counter after a successful attempt.
+// The ip counter is kept, so one client can't guess passwords of many accounts.
+func (s *AuthService) resetFailedAttempts(scope, account string) {
+	key := fmt.Sprintf("%s_account_%s", scope, account)
+
+	err := s.inMemory.Del(FailedAttemptsKey + key)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to reset failed attempts")
+	}
+}
+
+// registerWrongCode counts wrong guesses of the code stored under codeKey and returns incorrect
+// while guesses remain. The code is deleted once maxCodeAttempts is reached.
+func (s *AuthService) registerWrongCode(codeKey string, incorrect error) error {
+	count, err := s.inMemory.Incr(CodeAttemptsKey+codeKey, failedAttemptsWindow)
+	if err != nil {
+		return status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	if count < maxCodeAttempts {
+		return incorrect
+	}
+
+	err = s.inMemory.Del(codeKey)
+	if err != nil {
+		return status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	err = s.inMemory.Del(CodeAttemptsKey + codeKey)
+	if err != nil {
+		return status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	return status.Errorf(codes.ResourceExhausted, "too many incorrect codes, request a new code")
+}
+
+type attemptCounter struct {
+	key   string
+	limit int64
+}
+
+func attemptCounters(scope, account, ip string) []attemptCounter {
+	counters := []attemptCounter{
+		{key: fmt.Sprintf("%s_account_%s", scope, account), limit: maxAccountAttempts},
+	}
+	if ip != "" {
+		counters = append(counters, attemptCounter{key: fmt.Sprintf("%s_ip_%s", scope, ip), limit: maxIPAttempts})
+	}
+	return counters
+}
+
+func retryAfterError(retryAfter time.Duration) error {
+	st := status.Newf(codes.ResourceExhausted, "too many attempts, retry after %d seconds", int64(retryAfter.Seconds()))
+
+	st, err := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(retryAfter),
+	})
+	if err != nil {
+		return status.Errorf(codes.ResourceExhausted, "too many attempts, retry after %d seconds", int64(retryAfter.Seconds()))
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/config"
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type fakeStorage struct {
+	storage.StorageI
+	twoFactor *fakeTwoFactor
+	role      *fakeRole
+}
+
+func (s *fakeStorage) TwoFactor() repo.TwoFactorStorageI {
+	return s.twoFactor
+}
+
+// fakeTwoFactor has 2FA enabled for every user, or only enrolled when pending is set,
+// and accepts no recovery code
+type fakeTwoFactor struct {
+	repo.TwoFactorStorageI
+	secret  string
+	pending bool
+}
+
+func (f *fakeTwoFactor) Get(userID int64) (*repo.TwoFactor, error) {
+	return &repo.TwoFactor{UserID: userID, Secret: f.secret, Enabled: !f.pending}, nil
+}
+
+func (f *fakeTwoFactor) UseRecoveryCode(userID int64, codeHash string) (bool, error) {
+	return false, nil
+}
+
+func newTestAuthService(t *testing.T) (*AuthService, *fakeInMemory) {
+	cfg := &config.Config{AuthSecretKey: "secret_key"}
+
+	keys, err := utils.NewKeySet(cfg)
+	require.NoError(t, err)
+
+	secret, err := utils.GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	inMemory := newFakeInMemory()
+	strg := &fakeStorage{twoFactor: &fakeTwoFactor{secret: secret}}
+
+	return NewAuthService(strg, inMemory, nil, keys, nil, cfg, logger), inMemory
+}
+
+func contextFromIP(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 5000},
+	})
+}
+
+func requireLockedOut(t *testing.T, err error) {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+
+	require.Len(t, st.Details(), 1)
+	retryInfo, ok := st.Details()[0].(*errdetails.RetryInfo)
+	require.True(t, ok)
+	require.Positive(t, retryInfo.RetryDelay.AsDuration())
+}
+
+func TestLockout(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	ctx := contextFromIP("10.0.0.1")
+
+	for i := 0; i < maxAccountAttempts; i++ {
+		require.NoError(t, s.checkLockout(ctx, attemptScopeLogin, "user@gmail.com"))
+		s.registerFailedAttempt(ctx, attemptScopeLogin, "user@gmail.com")
+	}
+	requireLockedOut(t, s.checkLockout(ctx, attemptScopeLogin, "user@gmail.com"))
+
+	// the lockout is kept to the scope and the account
+	require.NoError(t, s.checkLockout(ctx, attemptScopeReset, "user@gmail.com"))
+	require.NoError(t, s.checkLockout(ctx, attemptScopeLogin, "other@gmail.com"))
+
+	// one ip guessing many accounts is locked out as well
+	for i := maxAccountAttempts; i < maxIPAttempts; i++ {
+		s.registerFailedAttempt(ctx, attemptScopeLogin, "other@gmail.com")
+	}
+	requireLockedOut(t, s.checkLockout(ctx, attemptScopeLogin, "third@gmail.com"))
+	require.NoError(t, s.checkLockout(contextFromIP("10.0.0.2"), attemptScopeLogin, "third@gmail.com"))
+}
+
+func TestResetFailedAttempts(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	ctx := contextFromIP("10.0.0.1")
+
+	for i := 0; i < maxAccountAttempts-1; i++ {
+		s.registerFailedAttempt(ctx, attemptScopeLogin, "user@gmail.com")
+	}
+	s.resetFailedAttempts(attemptScopeLogin, "user@gmail.com")
+
+	// the counter starts over after a success
+	for i := 0; i < maxAccountAttempts-1; i++ {
+		s.registerFailedAttempt(ctx, attemptScopeLogin, "user@gmail.com")
+	}
+	require.NoError(t, s.checkLockout(ctx, attemptScopeLogin, "user@gmail.com"))
+}
+
+func TestRegisterWrongCode(t *testing.T) {
+	s, inMemory := newTestAuthService(t)
+	incorrect := status.Errorf(codes.InvalidArgument, "incorrect_code")
+
+	require.NoError(t, inMemory.Set(RegisterCodeKey+"user@gmail.com", "123456", time.Minute))
+
+	for i := 0; i < maxCodeAttempts-1; i++ {
+		require.Equal(t, incorrect, s.registerWrongCode(RegisterCodeKey+"user@gmail.com", incorrect))
+	}
+
+	err := s.registerWrongCode(RegisterCodeKey+"user@gmail.com", incorrect)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = inMemory.Get(RegisterCodeKey + "user@gmail.com")
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+}
+
+func TestLoginVerify2FALockout(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	ctx := contextFromIP("10.0.0.1")
+
+	challenge, err := s.createTwoFactorChallenge(1)
+	require.NoError(t, err)
+
+	req := &pb.LoginVerify2FARequest{ChallengeToken: challenge, Code: "000000"}
+
+	for i := 0; i < maxCodeAttempts-1; i++ {
+		_, err = s.LoginVerify2FA(ctx, req)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+
+	// the challenge is deleted after too many wrong codes
+	_, err = s.LoginVerify2FA(ctx, req)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	_, err = s.LoginVerify2FA(ctx, req)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// a new challenge, even from another ip, doesn't bring new guesses
+	challenge, err = s.createTwoFactorChallenge(1)
+	require.NoError(t, err)
+
+	_, err = s.LoginVerify2FA(contextFromIP("10.0.0.2"), &pb.LoginVerify2FARequest{ChallengeToken: challenge, Code: "000000"})
+	requireLockedOut(t, err)
+
+	// the lockout is per user
+	challenge, err = s.createTwoFactorChallenge(2)
+	require.NoError(t, err)
+
+	_, err = s.LoginVerify2FA(contextFromIP("10.0.0.2"), &pb.LoginVerify2FARequest{ChallengeToken: challenge, Code: "000000"})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestDisable2FALockout(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	ctx := contextFromIP("10.0.0.1")
+
+	token, _, err := s.keys.CreateToken(&utils.TokenParams{
+		UserID:   1,
+		Duration: time.Minute,
+	})
+	require.NoError(t, err)
+
+	req := &pb.Disable2FARequest{AccessToken: token, Code: "000000"}
+
+	for i := 0; i < maxAccountAttempts; i++ {
+		_, err = s.Disable2FA(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	_, err = s.Disable2FA(ctx, req)
+	requireLockedOut(t, err)
+}
+
+func TestConfirm2FALockout(t *testing.T) {
+	s, _ := newTestAuthService(t)
+	s.storage.(*fakeStorage).twoFactor.pending = true
+	ctx := contextFromIP("10.0.0.1")
+
+	token, _, err := s.keys.CreateToken(&utils.TokenParams{
+		UserID:   1,
+		Duration: time.Minute,
+	})
+	require.NoError(t, err)
+
+	req := &pb.Confirm2FARequest{AccessToken: token, Code: "000000"}
+
+	for i := 0; i < maxAccountAttempts; i++ {
+		_, err = s.Confirm2FA(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+
+	_, err = s.Confirm2FA(ctx, req)
+	requireLockedOut(t, err)
+}
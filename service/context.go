@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"strings"
+
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// accessTokenFromContext returns the bearer token from the authorization metadata
+func accessTokenFromContext(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return "", status.Errorf(codes.Unauthenticated, "authorization metadata is not provided")
+	}
+
+	token := values[0]
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = token[7:]
+	}
+
+	return strings.TrimSpace(token), nil
+}
+
+// isInternalCall reports whether the caller presented the internal service token
+func isInternalCall(ctx context.Context, internalToken string) bool {
+	if internalToken == "" {
+		return false
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get("x-internal-token")
+	if len(values) == 0 {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(values[0]), []byte(internalToken)) == 1
+}
+
+// clientInfoFromContext returns the user agent and the ip address of the caller.
+// The forwarded address is used only when the connection comes from a trusted proxy.
+func clientInfoFromContext(ctx context.Context, proxies utils.TrustedProxies) (userAgent, ipAddress string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if values := md.Get("grpcgateway-user-agent"); len(values) > 0 {
+		userAgent = values[0]
+	} else if values := md.Get("user-agent"); len(values) > 0 {
+		userAgent = values[0]
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ipAddress = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ipAddress); err == nil {
+			ipAddress = host
+		}
+	}
+
+	ipAddress = proxies.ClientIP(ipAddress, strings.Join(md.Get("x-forwarded-for"), ","))
+
+	return userAgent, ipAddress
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// UserEvent is the body of the webhook deliveries of the user events
+type UserEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	UserID     int64           `json:"user_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data,omitempty"`
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Follow makes the caller follow the user, following twice is not an error
+func (s *UserService) Follow(ctx context.Context, req *pb.FollowRequest) (*emptypb.Empty, error) {
+	caller, err := s.requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if caller.UserId == req.UserId {
+		return nil, status.Errorf(codes.InvalidArgument, "users can't follow themselves")
+	}
+
+	_, err = s.storage.User().Get(req.UserId)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		return nil, databaseError(err, "failed to get user")
+	}
+
+	interaction, err := s.storage.Block().GetInteraction(caller.UserId, req.UserId)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to check interaction")
+		return nil, status.Errorf(codes.Internal, "failed to follow user: %v", err)
+	}
+
+	if interaction.Blocking || interaction.BlockedBy {
+		return nil, status.Errorf(codes.FailedPrecondition, "users blocked by each other can't follow")
+	}
+
+	_, err = s.storage.Follow().Follow(caller.UserId, req.UserId)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to follow user")
+		return nil, databaseError(err, "failed to follow user")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserService) Unfollow(ctx context.Context, req *pb.FollowRequest) (*emptypb.Empty, error) {
+	caller, err := s.requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.storage.Follow().Unfollow(caller.UserId, req.UserId)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to unfollow user")
+		return nil, status.Errorf(codes.Internal, "failed to unfollow user: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UserService) ListFollowers(ctx context.Context, req *pb.ListFollowsRequest) (*pb.ListFollowsResponse, error) {
+	users, nextCursor, err := s.listRelatedUsers(ctx, req.UserId, req.Limit, req.Cursor, s.storage.Follow().GetFollowers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListFollowsResponse{
+		Users:      users,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func (s *UserService) ListFollowing(ctx context.Context, req *pb.ListFollowsRequest) (*pb.ListFollowsResponse, error) {
+	users, nextCursor, err := s.listRelatedUsers(ctx, req.UserId, req.Limit, req.Cursor, s.storage.Follow().GetFollowing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ListFollowsResponse{
+		Users:      users,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// listRelatedUsers returns a page of the users related to userID by list and the cursor of the next page
+func (s *UserService) listRelatedUsers(
+	ctx context.Context,
+	userID int64,
+	limit int32,
+	cursor string,
+	list func(params *repo.ListRelatedUsersParams) (*repo.ListRelatedUsersResult, error),
+) ([]*pb.User, string, error) {
+	caller, err := s.caller(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	params := repo.ListRelatedUsersParams{
+		UserID: userID,
+		Limit:  limit,
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = 10
+	} else if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	// only superadmins see the users that aren't active
+	if !isSuperadmin(caller) {
+		params.Status = repo.UserStatusActive
+	}
+
+	if cursor != "" {
+		var after repo.RelatedUserCursor
+		err = utils.DecodeCursor(cursor, &after)
+		if err != nil {
+			return nil, "", status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+		}
+		params.After = &after
+	}
+
+	result, err := list(&params)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to list related users")
+		return nil, "", status.Errorf(codes.Internal, "failed to list users: %v", err)
+	}
+
+	users := make([]*pb.User, 0, len(result.Users))
+	for _, user := range result.Users {
+		users = append(users, parseUserModel(user, viewFor(caller, user.ID)))
+	}
+
+	var nextCursor string
+	if result.NextCursor != nil {
+		nextCursor, err = utils.EncodeCursor(result.NextCursor)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to encode cursor")
+			return nil, "", status.Errorf(codes.Internal, "failed to list users: %v", err)
+		}
+	}
+
+	return users, nextCursor, nil
+}
+
+func (s *UserService) IsFollowing(ctx context.Context, req *pb.IsFollowingRequest) (*pb.IsFollowingResponse, error) {
+	following, err := s.storage.Follow().IsFollowing(req.FollowerId, req.FolloweeId)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to check follow")
+		return nil, status.Errorf(codes.Internal, "failed to check follow: %v", err)
+	}
+
+	return &pb.IsFollowingResponse{
+		Following: following,
+	}, nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/storage"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	IdempotencyKeyHeader = "idempotency-key"
+	IdempotencyKey       = "idempotency_"
+
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyLockTTL bounds how long a crashed request keeps its key busy
+	idempotencyLockTTL   = time.Minute
+	maxIdempotencyKeyLen = 255
+)
+
+// idempotentMethods are the mutating rpcs accepting an idempotency key. The rpcs issuing
+// tokens, secrets or recovery codes are left out, so no credential is ever stored in redis.
+var idempotentMethods = map[string]bool{
+	"/genproto.AuthService/Register":       true,
+	"/genproto.AuthService/ForgotPassword": true,
+	"/genproto.AuthService/ResetPassword":  true,
+	"/genproto.AuthService/Logout":         true,
+	"/genproto.AuthService/LogoutAll":      true,
+	"/genproto.AuthService/RevokeSession":  true,
+	"/genproto.AuthService/Disable2FA":     true,
+	"/genproto.AuthService/RevokeApiKey":   true,
+
+	"/genproto.UserService/Create":       true,
+	"/genproto.UserService/Update":       true,
+	"/genproto.UserService/Delete":       true,
+	"/genproto.UserService/Restore":      true,
+	"/genproto.UserService/UpdateStatus": true,
+	"/genproto.UserService/Follow":       true,
+	"/genproto.UserService/Unfollow":     true,
+	"/genproto.UserService/Block":        true,
+	"/genproto.UserService/Unblock":      true,
+	"/genproto.UserService/Mute":         true,
+	"/genproto.UserService/Unmute":       true,
+}
+
+// idempotencyRecord is stored under the key, the response is empty while the first request runs
+type idempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Response    []byte `json:"response,omitempty"`
+}
+
+// IdempotencyInterceptor replays the stored response of the first successful request
+// to the retries sent with the same idempotency key. Failed requests aren't stored,
+// so they can be retried with the same key. The requests are hashed with the secret,
+// so the hashes in redis can't be used to guess the passwords they contain.
+func IdempotencyInterceptor(inMemory storage.InMemoryStorageI, auth *AuthService, secret []byte, logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !idempotentMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(IdempotencyKeyHeader)
+		if len(values) == 0 || values[0] == "" {
+			return handler(ctx, req)
+		}
+
+		if len(values[0]) > maxIdempotencyKeyLen {
+			return nil, status.Errorf(codes.InvalidArgument, "idempotency key is longer than %d characters", maxIdempotencyKeyLen)
+		}
+
+		message, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		scope, ok := auth.idempotencyScope(ctx)
+		if !ok {
+			// the handler rejects the caller
+			return handler(ctx, req)
+		}
+
+		requestHash, err := hashRequest(secret, message)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to hash request: %v", err)
+		}
+
+		key := idempotencyRedisKey(info.FullMethod, scope, values[0])
+
+		lock, err := json.Marshal(idempotencyRecord{RequestHash: requestHash})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal: %v", err)
+		}
+
+		acquired, err := inMemory.SetNX(key, string(lock), idempotencyLockTTL)
+		if err != nil {
+			logger.WithError(err).Error("failed to set idempotency key")
+			return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		}
+
+		if !acquired {
+			return replayResponse(inMemory, key, requestHash)
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if err := inMemory.Del(key); err != nil {
+				logger.WithError(err).Error("failed to delete idempotency key")
+			}
+			return nil, err
+		}
+
+		err = storeResponse(inMemory, key, requestHash, resp)
+		if err != nil {
+			// the request succeeded, only its retries lose the replay
+			logger.WithError(err).Error("failed to store idempotent response")
+		}
+
+		return resp, nil
+	}
+}
+
+// idempotencyScope returns the caller the idempotency keys are scoped to, the user of
+// a verified token rather than the token, so every token of a user shares the keys.
+// It reports false when the caller presents a token that doesn't verify.
+func (s *AuthService) idempotencyScope(ctx context.Context) (string, bool) {
+	if isInternalCall(ctx, s.cfg.InternalServiceToken) {
+		return "internal", true
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("authorization")) == 0 {
+		return "anonymous", true
+	}
+
+	token, err := accessTokenFromContext(ctx)
+	if err != nil {
+		return "", false
+	}
+
+	payload, _, err := s.authenticate(token)
+	if err != nil {
+		return "", false
+	}
+
+	return "user_" + strconv.FormatInt(payload.UserId, 10), true
+}
+
+// idempotencyRedisKey scopes the key to the rpc and to the caller, so a key can't replay
+// the response of another caller
+func idempotencyRedisKey(method, scope, idempotencyKey string) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(scope))
+	h.Write([]byte{0})
+	h.Write([]byte(idempotencyKey))
+
+	return IdempotencyKey + hex.EncodeToString(h.Sum(nil))
+}
+
+func hashRequest(secret []byte, req proto.Message) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func storeResponse(inMemory storage.InMemoryStorageI, key, requestHash string, resp interface{}) error {
+	message, ok := resp.(proto.Message)
+	if !ok {
+		return errors.New("response is not a proto message")
+	}
+
+	wrapped, err := anypb.New(message)
+	if err != nil {
+		return err
+	}
+
+	b, err := proto.Marshal(wrapped)
+	if err != nil {
+		return err
+	}
+
+	record, err := json.Marshal(idempotencyRecord{
+		RequestHash: requestHash,
+		Response:    b,
+	})
+	if err != nil {
+		return err
+	}
+
+	return inMemory.Set(key, string(record), idempotencyTTL)
+}
+
+func replayResponse(inMemory storage.InMemoryStorageI, key, requestHash string) (interface{}, error) {
+	value, err := inMemory.Get(key)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			// the first request failed or the record expired in between
+			return nil, status.Errorf(codes.Aborted, "request with the same idempotency key was interrupted, retry it")
+		}
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	var record idempotencyRecord
+	err = json.Unmarshal([]byte(value), &record)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to unmarshal: %v", err)
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency key was used for a different request")
+	}
+
+	if len(record.Response) == 0 {
+		return nil, status.Errorf(codes.Aborted, "request with the same idempotency key is in progress")
+	}
+
+	var wrapped anypb.Any
+	err = proto.Unmarshal(record.Response, &wrapped)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to unmarshal: %v", err)
+	}
+
+	resp, err := wrapped.UnmarshalNew()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to unmarshal: %v", err)
+	}
+
+	return resp, nil
+}
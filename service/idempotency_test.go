@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var followInfo = &grpc.UnaryServerInfo{FullMethod: "/genproto.UserService/Follow"}
+
+// countingHandler returns an empty response, or err when it's set
+type countingHandler struct {
+	calls int
+	err   error
+}
+
+func (h *countingHandler) handle(ctx context.Context, req interface{}) (interface{}, error) {
+	h.calls++
+	if h.err != nil {
+		return nil, h.err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func newTestInterceptor(t *testing.T) (grpc.UnaryServerInterceptor, *AuthService, *fakeInMemory) {
+	auth, inMemory := newTestAuthService(t)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	return IdempotencyInterceptor(inMemory, auth, []byte("idempotency_secret_key"), logger), auth, inMemory
+}
+
+func contextWithKey(idempotencyKey, token string) context.Context {
+	md := metadata.Pairs(IdempotencyKeyHeader, idempotencyKey)
+	if token != "" {
+		md.Append("authorization", "Bearer "+token)
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func createTestToken(t *testing.T, auth *AuthService, userID int64) string {
+	token, _, err := auth.keys.CreateToken(&utils.TokenParams{
+		UserID:   userID,
+		Email:    "user@example.com",
+		Duration: time.Minute,
+	})
+	require.NoError(t, err)
+	return token
+}
+
+func requireCode(t *testing.T, err error, code codes.Code) {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, code, st.Code())
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	interceptor, auth, _ := newTestInterceptor(t)
+	handler := &countingHandler{}
+
+	ctx := contextWithKey("key", createTestToken(t, auth, 1))
+	req := &pb.FollowRequest{UserId: 2}
+
+	for i := 0; i < 3; i++ {
+		resp, err := interceptor(ctx, req, followInfo, handler.handle)
+		require.NoError(t, err)
+		require.True(t, proto.Equal(&emptypb.Empty{}, resp.(proto.Message)))
+	}
+	require.Equal(t, 1, handler.calls)
+
+	// another token of the same user replays the response
+	ctx = contextWithKey("key", createTestToken(t, auth, 1))
+	_, err := interceptor(ctx, req, followInfo, handler.handle)
+	require.NoError(t, err)
+	require.Equal(t, 1, handler.calls)
+
+	// another user doesn't
+	ctx = contextWithKey("key", createTestToken(t, auth, 3))
+	_, err = interceptor(ctx, req, followInfo, handler.handle)
+	require.NoError(t, err)
+	require.Equal(t, 2, handler.calls)
+}
+
+func TestIdempotencyHashMismatch(t *testing.T) {
+	interceptor, auth, _ := newTestInterceptor(t)
+	handler := &countingHandler{}
+
+	ctx := contextWithKey("key", createTestToken(t, auth, 1))
+
+	_, err := interceptor(ctx, &pb.FollowRequest{UserId: 2}, followInfo, handler.handle)
+	require.NoError(t, err)
+
+	_, err = interceptor(ctx, &pb.FollowRequest{UserId: 3}, followInfo, handler.handle)
+	requireCode(t, err, codes.InvalidArgument)
+	require.Equal(t, 1, handler.calls)
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	interceptor, auth, _ := newTestInterceptor(t)
+
+	ctx := contextWithKey("key", createTestToken(t, auth, 1))
+	req := &pb.FollowRequest{UserId: 2}
+
+	retry := &countingHandler{}
+	first := func(ctx context.Context, req interface{}) (interface{}, error) {
+		// the retry arrives while the first request runs
+		_, err := interceptor(ctx, req, followInfo, retry.handle)
+		requireCode(t, err, codes.Aborted)
+		return &emptypb.Empty{}, nil
+	}
+
+	_, err := interceptor(ctx, req, followInfo, first)
+	require.NoError(t, err)
+	require.Zero(t, retry.calls)
+}
+
+func TestIdempotencyErrorNotCached(t *testing.T) {
+	interceptor, auth, _ := newTestInterceptor(t)
+	handler := &countingHandler{err: status.Errorf(codes.Unavailable, "unavailable")}
+
+	ctx := contextWithKey("key", createTestToken(t, auth, 1))
+	req := &pb.FollowRequest{UserId: 2}
+
+	_, err := interceptor(ctx, req, followInfo, handler.handle)
+	requireCode(t, err, codes.Unavailable)
+
+	handler.err = nil
+	_, err = interceptor(ctx, req, followInfo, handler.handle)
+	require.NoError(t, err)
+	require.Equal(t, 2, handler.calls)
+}
+
+func TestIdempotencyInvalidToken(t *testing.T) {
+	interceptor, _, inMemory := newTestInterceptor(t)
+	handler := &countingHandler{err: status.Errorf(codes.Unauthenticated, "invalid token")}
+
+	ctx := contextWithKey("key", "invalid")
+
+	_, err := interceptor(ctx, &pb.FollowRequest{UserId: 2}, followInfo, handler.handle)
+	requireCode(t, err, codes.Unauthenticated)
+	require.Equal(t, 1, handler.calls)
+	require.Empty(t, inMemory.entries)
+}
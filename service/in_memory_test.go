@@ -0,0 +1,112 @@
+package service
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/storage"
+)
+
+type fakeEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// fakeInMemory is an in-process storage.InMemoryStorageI with the expirations of redis
+type fakeInMemory struct {
+	mu      sync.Mutex
+	entries map[string]fakeEntry
+}
+
+func newFakeInMemory() *fakeInMemory {
+	return &fakeInMemory{
+		entries: make(map[string]fakeEntry),
+	}
+}
+
+func (f *fakeInMemory) get(key string) (fakeEntry, bool) {
+	e, ok := f.entries[key]
+	if ok && !e.expiresAt.IsZero() && !e.expiresAt.After(time.Now()) {
+		delete(f.entries, key)
+		return fakeEntry{}, false
+	}
+	return e, ok
+}
+
+func expiresAt(exp time.Duration) time.Time {
+	if exp <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(exp)
+}
+
+func (f *fakeInMemory) Set(key, value string, exp time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.entries[key] = fakeEntry{value: value, expiresAt: expiresAt(exp)}
+	return nil
+}
+
+func (f *fakeInMemory) Get(key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	e, ok := f.get(key)
+	if !ok {
+		return "", storage.ErrKeyNotFound
+	}
+	return e.value, nil
+}
+
+func (f *fakeInMemory) Del(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.entries, key)
+	return nil
+}
+
+func (f *fakeInMemory) SetNX(key, value string, exp time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.get(key); ok {
+		return false, nil
+	}
+
+	f.entries[key] = fakeEntry{value: value, expiresAt: expiresAt(exp)}
+	return true, nil
+}
+
+func (f *fakeInMemory) Incr(key string, exp time.Duration) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	e, ok := f.get(key)
+	if !ok {
+		e = fakeEntry{value: "0", expiresAt: expiresAt(exp)}
+	}
+
+	count, err := strconv.ParseInt(e.value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	count++
+
+	e.value = strconv.FormatInt(count, 10)
+	f.entries[key] = e
+	return count, nil
+}
+
+func (f *fakeInMemory) TTL(key string) (time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	e, ok := f.get(key)
+	if !ok || e.expiresAt.IsZero() {
+		return 0, storage.ErrKeyNotFound
+	}
+	return time.Until(e.expiresAt), nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/config"
+	"github.com/TemurMannonov/medium_user_service/genproto/notification_service"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/sirupsen/logrus"
+
+	grpcPkg "github.com/TemurMannonov/medium_user_service/pkg/grpc_client"
+	"github.com/TemurMannonov/medium_user_service/storage"
+)
+
+const (
+	outboxBatchSize    = 20
+	outboxRelayTimeout = 10 * time.Second
+	// outboxLease keeps the claimed events from the other dispatchers while they are relayed,
+	// it outlasts a batch of events timing out one after another.
+	// The events of a dispatcher dying in the middle are picked up once it expires.
+	outboxLease = outboxRelayTimeout*outboxBatchSize + time.Minute
+
+	outboxMinBackoff = time.Second
+	outboxMaxBackoff = time.Hour
+)
+
+// errOutboxEventExpired dead-letters the event without further attempts
+var errOutboxEventExpired = errors.New("event expired")
+
+type outboxHandler func(ctx context.Context, event *repo.OutboxEvent) error
+
+// OutboxDispatcher relays the events written to the outbox by the storage,
+// retrying the failed ones with an exponential backoff
+type OutboxDispatcher struct {
+	storage    storage.StorageI
+	grpcClient grpcPkg.GrpcClientI
+	cfg        *config.Config
+	logger     *logrus.Logger
+	handlers   map[string]outboxHandler
+}
+
+func NewOutboxDispatcher(strg storage.StorageI, grpcConn grpcPkg.GrpcClientI, cfg *config.Config, logger *logrus.Logger) *OutboxDispatcher {
+	d := &OutboxDispatcher{
+		storage:    strg,
+		grpcClient: grpcConn,
+		cfg:        cfg,
+		logger:     logger,
+	}
+
+	d.handlers = map[string]outboxHandler{
+		repo.EventUserCreated:               d.relayUserEvent,
+		repo.EventUserUpdated:               d.relayUserEvent,
+		repo.EventUserDeleted:               d.relayUserEvent,
+		repo.EventUserVerificationRequested: d.sendVerificationEmail,
+		repo.EventPasswordResetRequested:    d.sendVerificationEmail,
+	}
+
+	return d
+}
+
+// Run relays the due events every poll interval, until ctx is done
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.OutboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *OutboxDispatcher) dispatch(ctx context.Context) {
+	for {
+		events, err := d.storage.Outbox().Claim(outboxBatchSize, outboxLease)
+		if err != nil {
+			d.logger.WithError(err).Error("failed to claim outbox events")
+			return
+		}
+
+		for _, event := range events {
+			d.relay(ctx, event)
+		}
+
+		if len(events) < outboxBatchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (d *OutboxDispatcher) relay(ctx context.Context, event *repo.OutboxEvent) {
+	logger := d.logger.WithFields(logrus.Fields{
+		"event_id": event.EventID,
+		"type":     event.Type,
+	})
+
+	var err error
+	handler, ok := d.handlers[event.Type]
+	if ok {
+		ctx, cancel := context.WithTimeout(ctx, outboxRelayTimeout)
+		err = handler(ctx, event)
+		cancel()
+	} else {
+		err = fmt.Errorf("unknown event type: %s", event.Type)
+	}
+
+	if err == nil {
+		err = d.storage.Outbox().MarkSent(event.ID)
+		if err != nil {
+			logger.WithError(err).Error("failed to mark outbox event as sent")
+		}
+		return
+	}
+
+	// an unknown type won't get a handler by retrying
+	dead := !ok || errors.Is(err, errOutboxEventExpired) || event.Attempts+1 >= d.cfg.OutboxMaxAttempts
+	if dead {
+		logger.WithError(err).Error("outbox event is dead-lettered")
+	} else {
+		logger.WithError(err).Warn("failed to relay outbox event")
+	}
+
+	err = d.storage.Outbox().MarkFailed(event.ID, err.Error(), time.Now().Add(outboxBackoff(event.Attempts)), dead)
+	if err != nil {
+		logger.WithError(err).Error("failed to mark outbox event as failed")
+	}
+}
+
+// outboxBackoff doubles the delay with every attempt, the jitter spreads the retries
+// of the events failing together
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxMaxBackoff
+	if attempts < 32 {
+		backoff = outboxMinBackoff << attempts
+	}
+	if backoff > outboxMaxBackoff || backoff <= 0 {
+		backoff = outboxMaxBackoff
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// relayUserEvent queues the event for the subscribed webhooks, the webhook deliveries are
+// the only way the user events leave the service. Queueing is idempotent, so a retry
+// doesn't deliver twice.
+func (d *OutboxDispatcher) relayUserEvent(ctx context.Context, event *repo.OutboxEvent) error {
+	data, err := json.Marshal(UserEvent{
+		ID:         event.EventID,
+		Type:       event.Type,
+		UserID:     event.UserID,
+		OccurredAt: event.CreatedAt.UTC(),
+		Data:       event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	return d.storage.Webhook().CreateDeliveries(event.EventID, event.Type, data)
+}
+
+func (d *OutboxDispatcher) sendVerificationEmail(ctx context.Context, event *repo.OutboxEvent) error {
+	// the code is gone from redis by now, the email would be useless
+	if time.Since(event.CreatedAt) > VerificationCodeTTL {
+		return errOutboxEventExpired
+	}
+
+	var payload repo.VerificationCodePayload
+	err := json.Unmarshal(event.Payload, &payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.grpcClient.NotificationService().SendEmail(ctx, &notification_service.SendEmailRequest{
+		To:      payload.Email,
+		Subject: "Verification email",
+		Body: map[string]string{
+			"code": payload.Code,
+		},
+		Type: "verification_email",
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,504 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type PermissionService struct {
+	pb.UnimplementedPermissionServiceServer
+	storage storage.StorageI
+	auth    *AuthService
+	logger  *logrus.Logger
+}
+
+func NewPermissionService(strg storage.StorageI, auth *AuthService, logger *logrus.Logger) *PermissionService {
+	return &PermissionService{
+		storage: strg,
+		auth:    auth,
+		logger:  logger,
+	}
+}
+
+func (s *PermissionService) CreatePermission(ctx context.Context, req *pb.Permission) (*pb.Permission, error) {
+	payload, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.UserType == "" || req.Resource == "" || req.Action == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_type, resource and action are required")
+	}
+
+	condition, err := validateCondition(req.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := s.storage.Permission().Create(&repo.Permission{
+		UserType:  req.UserType,
+		Resource:  req.Resource,
+		Action:    req.Action,
+		Condition: condition,
+	}, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to create permission")
+		return nil, databaseError(err, "failed to create permission")
+	}
+	s.auth.invalidatePermissionSets(permission.UserType)
+
+	return parsePermissionModel(permission), nil
+}
+
+func (s *PermissionService) GetPermission(ctx context.Context, req *pb.IdRequest) (*pb.Permission, error) {
+	_, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := s.storage.Permission().Get(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get permission")
+		return nil, databaseError(err, "failed to get permission")
+	}
+
+	return parsePermissionModel(permission), nil
+}
+
+func (s *PermissionService) GetAllPermissions(ctx context.Context, req *pb.GetAllPermissionsRequest) (*pb.GetAllPermissionsResponse, error) {
+	_, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.storage.Permission().GetAll(&repo.GetAllPermissionsParams{
+		UserType: req.UserType,
+		Resource: req.Resource,
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get all permissions")
+		return nil, status.Errorf(codes.Internal, "failed to get all permissions: %v", err)
+	}
+
+	response := pb.GetAllPermissionsResponse{
+		Permissions: make([]*pb.Permission, 0),
+	}
+
+	for _, permission := range permissions {
+		response.Permissions = append(response.Permissions, parsePermissionModel(permission))
+	}
+
+	return &response, nil
+}
+
+func (s *PermissionService) UpdatePermission(ctx context.Context, req *pb.Permission) (*pb.Permission, error) {
+	payload, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.UserType == "" || req.Resource == "" || req.Action == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_type, resource and action are required")
+	}
+
+	condition, err := validateCondition(req.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := s.storage.Permission().Get(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get permission")
+		return nil, databaseError(err, "failed to get permission")
+	}
+
+	permission, err := s.storage.Permission().Update(&repo.Permission{
+		ID:        req.Id,
+		UserType:  req.UserType,
+		Resource:  req.Resource,
+		Action:    req.Action,
+		Condition: condition,
+	}, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to update permission")
+		return nil, databaseError(err, "failed to update permission")
+	}
+	s.auth.invalidatePermissionSets(old.UserType, permission.UserType)
+
+	return parsePermissionModel(permission), nil
+}
+
+func (s *PermissionService) DeletePermission(ctx context.Context, req *pb.IdRequest) (*emptypb.Empty, error) {
+	payload, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permission, err := s.storage.Permission().Get(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get permission")
+		return nil, databaseError(err, "failed to get permission")
+	}
+
+	err = s.storage.Permission().Delete(req.Id, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to delete permission")
+		return nil, databaseError(err, "failed to delete permission")
+	}
+	s.auth.invalidatePermissionSets(permission.UserType)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *PermissionService) CreateRole(ctx context.Context, req *pb.Role) (*pb.Role, error) {
+	payload, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name is required")
+	}
+
+	role, err := s.storage.Role().Create(&repo.Role{
+		Name:        req.Name,
+		Description: req.Description,
+	}, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to create role")
+		return nil, databaseError(err, "failed to create role")
+	}
+
+	return parseRoleModel(role), nil
+}
+
+func (s *PermissionService) GetRole(ctx context.Context, req *pb.IdRequest) (*pb.Role, error) {
+	_, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.storage.Role().Get(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get role")
+		return nil, databaseError(err, "failed to get role")
+	}
+
+	return parseRoleModel(role), nil
+}
+
+func (s *PermissionService) GetAllRoles(ctx context.Context, req *emptypb.Empty) (*pb.GetAllRolesResponse, error) {
+	_, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.storage.Role().GetAll()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get all roles")
+		return nil, status.Errorf(codes.Internal, "failed to get all roles: %v", err)
+	}
+
+	response := pb.GetAllRolesResponse{
+		Roles: make([]*pb.Role, 0),
+	}
+
+	for _, role := range roles {
+		response.Roles = append(response.Roles, parseRoleModel(role))
+	}
+
+	return &response, nil
+}
+
+func (s *PermissionService) UpdateRole(ctx context.Context, req *pb.Role) (*pb.Role, error) {
+	payload, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "name is required")
+	}
+
+	old, err := s.storage.Role().Get(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get role")
+		return nil, databaseError(err, "failed to get role")
+	}
+
+	if isBuiltInRole(old.Name) && old.Name != req.Name {
+		return nil, status.Errorf(codes.FailedPrecondition, "built-in role %s can't be renamed", old.Name)
+	}
+
+	role, err := s.storage.Role().Update(&repo.Role{
+		ID:          req.Id,
+		Name:        req.Name,
+		Description: req.Description,
+	}, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to update role")
+		return nil, databaseError(err, "failed to update role")
+	}
+	// permissions follow the role name on rename
+	s.auth.invalidatePermissionSets(old.Name, role.Name)
+
+	return parseRoleModel(role), nil
+}
+
+func (s *PermissionService) DeleteRole(ctx context.Context, req *pb.IdRequest) (*emptypb.Empty, error) {
+	payload, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.storage.Role().Get(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get role")
+		return nil, databaseError(err, "failed to get role")
+	}
+
+	if isBuiltInRole(role.Name) {
+		return nil, status.Errorf(codes.FailedPrecondition, "built-in role %s can't be deleted", role.Name)
+	}
+
+	userIDs, err := s.storage.Role().Delete(req.Id, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to delete role")
+		return nil, databaseError(err, "failed to delete role")
+	}
+	s.auth.invalidatePermissionSets(role.Name)
+
+	err = s.invalidateUserRoles(userIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *PermissionService) GetUserRoles(ctx context.Context, req *pb.IdRequest) (*pb.GetAllRolesResponse, error) {
+	_, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.storage.Role().GetUserRoles(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user roles")
+		return nil, status.Errorf(codes.Internal, "failed to get user roles: %v", err)
+	}
+
+	response := pb.GetAllRolesResponse{
+		Roles: make([]*pb.Role, 0),
+	}
+
+	for _, role := range roles {
+		response.Roles = append(response.Roles, parseRoleModel(role))
+	}
+
+	return &response, nil
+}
+
+func (s *PermissionService) AssignRole(ctx context.Context, req *pb.UserRoleRequest) (*emptypb.Empty, error) {
+	payload, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.Role().AssignToUser(req.UserId, req.RoleId, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to assign role")
+		return nil, databaseError(err, "failed to assign role")
+	}
+
+	err = s.invalidateUserRoles(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *PermissionService) UnassignRole(ctx context.Context, req *pb.UserRoleRequest) (*emptypb.Empty, error) {
+	payload, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.Role().UnassignFromUser(req.UserId, req.RoleId, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to unassign role")
+		return nil, databaseError(err, "failed to unassign role")
+	}
+
+	err = s.invalidateUserRoles(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// invalidateUserRoles drops the role list carried by the access tokens of the users,
+// their sessions stay and the tokens read the current roles instead
+func (s *PermissionService) invalidateUserRoles(userIDs ...int64) error {
+	err := s.auth.invalidateUserRoles(userIDs...)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to invalidate user roles")
+		return status.Errorf(codes.Internal, "failed to invalidate user roles: %v", err)
+	}
+
+	return nil
+}
+
+func (s *PermissionService) GetAllAuditLogs(ctx context.Context, req *pb.GetAllAuditLogsRequest) (*pb.GetAllAuditLogsResponse, error) {
+	_, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, page := req.Limit, req.Page
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	result, err := s.storage.AuditLog().GetAll(&repo.GetAllAuditLogsParams{
+		Limit:    limit,
+		Page:     page,
+		Entity:   req.Entity,
+		EntityID: req.EntityId,
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get all audit logs")
+		return nil, status.Errorf(codes.Internal, "failed to get all audit logs: %v", err)
+	}
+
+	response := pb.GetAllAuditLogsResponse{
+		Count:     result.Count,
+		AuditLogs: make([]*pb.AuditLog, 0),
+	}
+
+	for _, l := range result.AuditLogs {
+		response.AuditLogs = append(response.AuditLogs, &pb.AuditLog{
+			Id:        l.ID,
+			ActorId:   l.ActorID,
+			Action:    l.Action,
+			Entity:    l.Entity,
+			EntityId:  l.EntityID,
+			OldValue:  l.OldValue,
+			NewValue:  l.NewValue,
+			CreatedAt: l.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &response, nil
+}
+
+// authorizeSuperadmin verifies the access token from the metadata and allows only superadmins
+func (s *PermissionService) authorizeSuperadmin(ctx context.Context) (*utils.Payload, error) {
+	accessToken, err := accessTokenFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := s.auth.verifyAccessToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !payload.HasRole(repo.UserTypeSuperadmin) {
+		return nil, status.Errorf(codes.PermissionDenied, "only superadmins can manage permissions")
+	}
+
+	return payload, nil
+}
+
+// validateCondition returns the permission condition, "any" when it is not set
+func validateCondition(condition string) (string, error) {
+	switch condition {
+	case "":
+		return repo.ConditionAny, nil
+	case repo.ConditionAny, repo.ConditionOwn:
+		return condition, nil
+	}
+	return "", status.Errorf(codes.InvalidArgument, "condition must be one of: %s, %s", repo.ConditionAny, repo.ConditionOwn)
+}
+
+// isAllowed decides the access by the permission condition. Permissions with the "own"
+// condition allow the action only when the caller owns the resource.
+func isAllowed(condition string, resourceOwnerID, userID int64) bool {
+	switch condition {
+	case repo.ConditionAny:
+		return true
+	case repo.ConditionOwn:
+		return resourceOwnerID != 0 && resourceOwnerID == userID
+	}
+	return false
+}
+
+func isBuiltInRole(name string) bool {
+	return name == repo.UserTypeSuperadmin || name == repo.UserTypeUser
+}
+
+func parsePermissionModel(permission *repo.Permission) *pb.Permission {
+	return &pb.Permission{
+		Id:        permission.ID,
+		UserType:  permission.UserType,
+		Resource:  permission.Resource,
+		Action:    permission.Action,
+		Condition: permission.Condition,
+	}
+}
+
+func parseRoleModel(role *repo.Role) *pb.Role {
+	return &pb.Role{
+		Id:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		CreatedAt:   role.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// databaseError converts storage errors to grpc status errors
+func databaseError(err error, message string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.NotFound, err.Error())
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Name() {
+		case "unique_violation":
+			return status.Errorf(codes.AlreadyExists, "%s: %v", message, pqErr.Message)
+		case "foreign_key_violation":
+			return status.Errorf(codes.FailedPrecondition, "%s: %v", message, pqErr.Message)
+		}
+	}
+
+	return status.Errorf(codes.Internal, "%s: %v", message, err)
+}
+
+// createUserError tells apart the email taken by a soft deleted user, which keeps it until it's purged
+func createUserError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" && pqErr.Constraint == "users_email_key" {
+		return status.Errorf(codes.AlreadyExists, "email is already registered, a deleted account keeps its email until it's purged")
+	}
+
+	return databaseError(err, "failed to create user")
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/storage"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	PermissionSetKey = "permission_set_"
+
+	// permissionSetTTL bounds how long a cached permission set may outlive a missed invalidation
+	permissionSetTTL = time.Hour
+
+	maxPermissionChecks = 100
+)
+
+// permissionSet maps "resource:action" to the condition a role is granted it under
+type permissionSet map[string]string
+
+func permissionSetKey(resource, action string) string {
+	return resource + ":" + action
+}
+
+// CheckPermissions verifies the token once and answers every resource/action pair of the request
+func (s *AuthService) CheckPermissions(ctx context.Context, req *pb.CheckPermissionsRequest) (*pb.CheckPermissionsResponse, error) {
+	if len(req.Checks) > maxPermissionChecks {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d checks are allowed", maxPermissionChecks)
+	}
+
+	payload, apiKey, err := s.authenticate(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	response := pb.CheckPermissionsResponse{
+		Payload: payload,
+		Results: make([]*pb.PermissionCheckResult, 0, len(req.Checks)),
+	}
+
+	for _, check := range req.Checks {
+		condition, hasPermission, err := s.checkPermission(payload, apiKey, check.Resource, check.Action, check.ResourceOwnerId)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to check permission")
+			return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+		}
+
+		response.Results = append(response.Results, &pb.PermissionCheckResult{
+			Resource:      check.Resource,
+			Action:        check.Action,
+			HasPermission: hasPermission,
+			Condition:     condition,
+		})
+	}
+
+	return &response, nil
+}
+
+// authenticate verifies an access token or an api key. The api key is nil for access tokens.
+func (s *AuthService) authenticate(token string) (*pb.AuthPayload, *repo.ApiKey, error) {
+	if strings.HasPrefix(token, ApiKeyPrefix) {
+		return s.verifyApiKey(token)
+	}
+
+	payload, err := s.verifyAccessToken(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &pb.AuthPayload{
+		Id:        payload.ID.String(),
+		UserId:    payload.UserID,
+		Email:     payload.Email,
+		UserType:  payload.UserType,
+		IssuedAt:  payload.IssuedAt.Format(time.RFC3339),
+		ExpiredAt: payload.ExpiredAt.Format(time.RFC3339),
+		SessionId: payload.SessionID,
+		TokenType: TokenTypeAccessToken,
+		Roles:     payload.Roles,
+	}, nil, nil
+}
+
+// checkPermission returns the condition the caller is granted the action under and whether
+// it allows the action on the resource. Api keys are limited to their scopes.
+func (s *AuthService) checkPermission(payload *pb.AuthPayload, apiKey *repo.ApiKey, resource, action string, resourceOwnerID int64) (string, bool, error) {
+	if apiKey != nil && !apiKey.HasScope(resource, action) {
+		return "", false, nil
+	}
+
+	condition, err := s.getCondition(payload.Roles, resource, action)
+	if err != nil {
+		return "", false, err
+	}
+
+	return condition, isAllowed(condition, resourceOwnerID, payload.UserId), nil
+}
+
+// getCondition returns the widest condition any of the roles is granted the action under
+func (s *AuthService) getCondition(roles []string, resource, action string) (string, error) {
+	var result string
+	for _, role := range roles {
+		if role == "" {
+			continue
+		}
+
+		set, err := s.getPermissionSet(role)
+		if err != nil {
+			return "", err
+		}
+
+		condition, ok := set[permissionSetKey(resource, action)]
+		if !ok {
+			continue
+		}
+
+		if condition == repo.ConditionAny {
+			return condition, nil
+		}
+		result = condition
+	}
+
+	return result, nil
+}
+
+// getPermissionSet returns the permissions of the role, loading them into the cache on a miss
+func (s *AuthService) getPermissionSet(role string) (permissionSet, error) {
+	data, err := s.inMemory.Get(PermissionSetKey + role)
+	if err == nil {
+		var set permissionSet
+		err = json.Unmarshal([]byte(data), &set)
+		if err == nil {
+			return set, nil
+		}
+		s.logger.WithError(err).Error("failed to unmarshal permission set")
+	} else if !errors.Is(err, storage.ErrKeyNotFound) {
+		s.logger.WithError(err).Error("failed to get permission set")
+	}
+
+	permissions, err := s.storage.Permission().GetAll(&repo.GetAllPermissionsParams{
+		UserType: role,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(permissionSet, len(permissions))
+	for _, p := range permissions {
+		key := permissionSetKey(p.Resource, p.Action)
+		if set[key] != repo.ConditionAny {
+			set[key] = p.Condition
+		}
+	}
+
+	value, err := json.Marshal(set)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.inMemory.Set(PermissionSetKey+role, string(value), permissionSetTTL)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to cache permission set")
+	}
+
+	return set, nil
+}
+
+// invalidatePermissionSets drops the cached permissions of the roles
+func (s *AuthService) invalidatePermissionSets(roles ...string) {
+	for _, role := range roles {
+		err := s.inMemory.Del(PermissionSetKey + role)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to invalidate permission set")
+		}
+	}
+}
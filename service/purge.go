@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	purgeBatchSize = 100
+
+	followCountSyncInterval  = 10 * time.Second
+	followCountSyncBatchSize = 100
+)
+
+// RunPurgeWorker purges the users deleted longer than the grace period ago and syncs the
+// follow counts with the deleted and restored users, until ctx is done
+func (s *UserService) RunPurgeWorker(ctx context.Context) {
+	purgeTicker := time.NewTicker(s.cfg.UserPurgeInterval)
+	defer purgeTicker.Stop()
+
+	syncTicker := time.NewTicker(followCountSyncInterval)
+	defer syncTicker.Stop()
+
+	s.syncFollowCounts()
+	s.purgeDeletedUsers()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-syncTicker.C:
+			s.syncFollowCounts()
+		case <-purgeTicker.C:
+			s.purgeDeletedUsers()
+		}
+	}
+}
+
+// syncFollowCounts moves the follows of the deleted and restored users out of or into
+// the counts of the other users, the users failing to sync are retried on the next tick
+func (s *UserService) syncFollowCounts() {
+	ids, err := s.storage.User().GetUnsyncedFollowCounts(followCountSyncBatchSize)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get users with unsynced follow counts")
+		return
+	}
+
+	for _, id := range ids {
+		err := s.storage.User().SyncFollowCounts(id)
+		if err != nil {
+			s.logger.WithError(err).WithField("user_id", id).Warn("failed to sync follow counts")
+		}
+	}
+}
+
+func (s *UserService) purgeDeletedUsers() {
+	for {
+		ids, err := s.storage.User().GetDeletedBefore(time.Now().Add(-s.cfg.UserPurgeGracePeriod), purgeBatchSize)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to get deleted users")
+			return
+		}
+
+		purged := 0
+		for _, id := range ids {
+			err := s.storage.User().Purge(id)
+			if err != nil {
+				// another instance may have purged the user already
+				if !errors.Is(err, sql.ErrNoRows) {
+					s.logger.WithError(err).WithField("user_id", id).Error("failed to purge user")
+				}
+				continue
+			}
+			purged++
+		}
+
+		// a batch failing as a whole is retried on the next tick
+		if len(ids) < purgeBatchSize || purged == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestSuperadminTypeWithoutRoles(t *testing.T) {
+	auth, _ := newTestAuthService(t)
+
+	token, _, err := auth.keys.CreateToken(&utils.TokenParams{
+		UserID:   1,
+		UserType: repo.UserTypeSuperadmin,
+		Duration: time.Minute,
+	})
+	require.NoError(t, err)
+
+	payload, _, err := auth.authenticate(token)
+	require.NoError(t, err)
+	require.Empty(t, payload.Roles)
+	require.False(t, isSuperadmin(payload))
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	webhooks := NewWebhookService(auth.storage, auth, logger)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	_, err = webhooks.ListWebhooks(ctx, &emptypb.Empty{})
+	requireCode(t, err, codes.PermissionDenied)
+}
+
+// fakeRole assigns the same roles to every user
+type fakeRole struct {
+	repo.RoleStorageI
+	roles []string
+}
+
+func (s *fakeStorage) Role() repo.RoleStorageI {
+	return s.role
+}
+
+func (f *fakeRole) GetUserRoles(userID int64) ([]*repo.Role, error) {
+	result := make([]*repo.Role, 0, len(f.roles))
+	for _, name := range f.roles {
+		result = append(result, &repo.Role{Name: name})
+	}
+	return result, nil
+}
+
+func TestInvalidateUserRoles(t *testing.T) {
+	auth, _ := newTestAuthService(t)
+	role := &fakeRole{}
+	auth.storage.(*fakeStorage).role = role
+
+	token, _, err := auth.keys.CreateToken(&utils.TokenParams{
+		UserID:   1,
+		Roles:    []string{repo.UserTypeSuperadmin},
+		Duration: time.Minute,
+	})
+	require.NoError(t, err)
+
+	payload, _, err := auth.authenticate(token)
+	require.NoError(t, err)
+	require.True(t, isSuperadmin(payload))
+
+	// the role is unassigned, the token stays valid without it
+	require.NoError(t, auth.invalidateUserRoles(1))
+
+	payload, _, err = auth.authenticate(token)
+	require.NoError(t, err)
+	require.False(t, isSuperadmin(payload))
+
+	role.roles = []string{repo.UserTypeSuperadmin}
+	payload, _, err = auth.authenticate(token)
+	require.NoError(t, err)
+	require.True(t, isSuperadmin(payload))
+
+	// the mark is per user
+	other, _, err := auth.keys.CreateToken(&utils.TokenParams{
+		UserID:   2,
+		Duration: time.Minute,
+	})
+	require.NoError(t, err)
+
+	payload, _, err = auth.authenticate(other)
+	require.NoError(t, err)
+	require.Empty(t, payload.Roles)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func (s *AuthService) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	payload, err := s.verifyAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.storage.Session().GetAllByUser(payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get sessions")
+		return nil, status.Errorf(codes.Internal, "failed to get sessions: %v", err)
+	}
+
+	response := pb.ListSessionsResponse{
+		Sessions: make([]*pb.Session, 0),
+	}
+
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, &pb.Session{
+			Id:         session.ID,
+			UserAgent:  session.UserAgent,
+			IpAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+			LastSeenAt: session.LastSeenAt.Format(time.RFC3339),
+			ExpiresAt:  session.ExpiresAt.Format(time.RFC3339),
+			Current:    session.ID == payload.SessionID,
+		})
+	}
+
+	return &response, nil
+}
+
+func (s *AuthService) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*emptypb.Empty, error) {
+	payload, err := s.verifyAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.storage.Session().Get(req.SessionId)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get session")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "session not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get session: %v", err)
+	}
+
+	if session.UserID != payload.UserID {
+		return nil, status.Errorf(codes.NotFound, "session not found")
+	}
+
+	err = s.revokeSession(session.ID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to revoke session")
+		return nil, status.Errorf(codes.Internal, "failed to revoke session: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/google/uuid"
+)
+
+const (
+	RefreshTokenKey        = "refresh_token_"
+	RefreshTokenUsedKey    = "refresh_token_used_"
+	RevokedSessionKey      = "revoked_session_"
+	refreshTokenByteLength = 32
+)
+
+// refreshTokenData is stored in redis under the hash of the refresh token.
+// All refresh tokens obtained from one login share the same SessionID,
+// so that the whole chain can be revoked when a used token is replayed.
+type refreshTokenData struct {
+	UserID    int64     `json:"user_id"`
+	SessionID string    `json:"session_id"`
+	IssuedAt  time.Time `json:"issued_at"`
+}
+
+// createAuthResponse issues a new access token and a new refresh token for the user.
+// An empty sessionID starts a new session, otherwise the existing session is
+// updated with the new token id, client info and last seen time.
+func (s *AuthService) createAuthResponse(ctx context.Context, user *repo.User, sessionID string) (*pb.AuthResponse, error) {
+	newSession := sessionID == ""
+	if newSession {
+		sessionID = uuid.NewString()
+	}
+
+	roles, err := s.getUserRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, payload, err := s.keys.CreateToken(&utils.TokenParams{
+		UserID:    user.ID,
+		Email:     user.Email,
+		UserType:  user.Type,
+		Roles:     roles,
+		SessionID: sessionID,
+		Duration:  s.cfg.AccessTokenDuration,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent, ipAddress := clientInfoFromContext(ctx, s.trustedProxies)
+	session := &repo.Session{
+		ID:        sessionID,
+		UserID:    user.ID,
+		TokenID:   payload.ID.String(),
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+		ExpiresAt: time.Now().Add(s.cfg.RefreshTokenDuration),
+	}
+
+	if newSession {
+		_, err = s.storage.Session().Create(session)
+	} else {
+		err = s.storage.Session().UpdateToken(session)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := s.createRefreshToken(user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AuthResponse{
+		Id:           user.ID,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		Email:        user.Email,
+		Username:     user.Username,
+		Type:         user.Type,
+		Roles:        roles,
+		CreatedAt:    user.CreatedAt.Format(time.RFC3339),
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (s *AuthService) createRefreshToken(userID int64, sessionID string) (string, error) {
+	token, err := utils.GenerateRandomToken(refreshTokenByteLength)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(refreshTokenData{
+		UserID:    userID,
+		SessionID: sessionID,
+		IssuedAt:  time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = s.inMemory.Set(RefreshTokenKey+utils.HashToken(token), string(data), s.cfg.RefreshTokenDuration)
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// revokeSession invalidates the session's refresh tokens and all access tokens issued within it
+func (s *AuthService) revokeSession(sessionID string) error {
+	err := s.inMemory.Set(RevokedSessionKey+sessionID, "1", s.cfg.RefreshTokenDuration)
+	if err != nil {
+		return err
+	}
+
+	err = s.storage.Session().Revoke(sessionID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *AuthService) isSessionRevoked(sessionID string) (bool, error) {
+	_, err := s.inMemory.Get(RevokedSessionKey + sessionID)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// getUserRoles returns the names of all roles assigned to the user. The type of the user
+// grants nothing by itself, so an unassigned role can't come back with the next token.
+func (s *AuthService) getUserRoles(userID int64) ([]string, error) {
+	roles, err := s.storage.Role().GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(roles))
+	for _, role := range roles {
+		result = append(result, role.Name)
+	}
+
+	return result, nil
+}
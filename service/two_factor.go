@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	TwoFactorChallengeKey = "two_factor_challenge_"
+	TOTPUsedCodeKey       = "totp_used_code_"
+
+	totpIssuer               = "Medium"
+	twoFactorChallengeTTL    = 5 * time.Minute
+	recoveryCodesCount       = 10
+	challengeTokenByteLength = 32
+)
+
+func (s *AuthService) Enable2FA(ctx context.Context, req *pb.Enable2FARequest) (*pb.Enable2FAResponse, error) {
+	payload, err := s.verifyAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate secret: %v", err)
+	}
+
+	err = s.storage.TwoFactor().SetSecret(payload.UserID, secret)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to set totp secret")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.FailedPrecondition, "two factor authentication is already enabled")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to set totp secret: %v", err)
+	}
+
+	return &pb.Enable2FAResponse{
+		Secret:     secret,
+		OtpauthUri: utils.TOTPURI(totpIssuer, payload.Email, secret),
+	}, nil
+}
+
+func (s *AuthService) Confirm2FA(ctx context.Context, req *pb.Confirm2FARequest) (*pb.Confirm2FAResponse, error) {
+	payload, err := s.verifyAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := s.storage.TwoFactor().Get(payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get two factor settings")
+		return nil, status.Errorf(codes.Internal, "failed to get two factor settings: %v", err)
+	}
+
+	if twoFactor.Enabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "two factor authentication is already enabled")
+	}
+
+	if twoFactor.Secret == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "two factor authentication is not enrolled")
+	}
+
+	account := strconv.FormatInt(payload.UserID, 10)
+
+	err = s.checkLockout(ctx, attemptScopeTwoFactor, account)
+	if err != nil {
+		return nil, err
+	}
+
+	if !utils.ValidateTOTPCode(twoFactor.Secret, req.Code, time.Now()) {
+		s.registerFailedAttempt(ctx, attemptScopeTwoFactor, account)
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect_code")
+	}
+
+	s.resetFailedAttempts(attemptScopeTwoFactor, account)
+
+	recoveryCodes, err := utils.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate recovery codes: %v", err)
+	}
+
+	hashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashes = append(hashes, utils.HashToken(code))
+	}
+
+	err = s.storage.TwoFactor().Enable(payload.UserID, hashes)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to enable two factor authentication")
+		return nil, status.Errorf(codes.Internal, "failed to enable two factor authentication: %v", err)
+	}
+
+	return &pb.Confirm2FAResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (s *AuthService) Disable2FA(ctx context.Context, req *pb.Disable2FARequest) (*emptypb.Empty, error) {
+	payload, err := s.verifyAccessToken(req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := s.storage.TwoFactor().Get(payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get two factor settings")
+		return nil, status.Errorf(codes.Internal, "failed to get two factor settings: %v", err)
+	}
+
+	if !twoFactor.Enabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "two factor authentication is not enabled")
+	}
+
+	account := strconv.FormatInt(payload.UserID, 10)
+
+	err = s.checkLockout(ctx, attemptScopeTwoFactor, account)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.checkTwoFactorCode(twoFactor.UserID, twoFactor.Secret, req.Code)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	if !ok {
+		s.registerFailedAttempt(ctx, attemptScopeTwoFactor, account)
+		return nil, status.Errorf(codes.InvalidArgument, "incorrect_code")
+	}
+
+	s.resetFailedAttempts(attemptScopeTwoFactor, account)
+
+	err = s.storage.TwoFactor().Disable(payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to disable two factor authentication")
+		return nil, status.Errorf(codes.Internal, "failed to disable two factor authentication: %v", err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *AuthService) LoginVerify2FA(ctx context.Context, req *pb.LoginVerify2FARequest) (*pb.AuthResponse, error) {
+	challengeKey := TwoFactorChallengeKey + utils.HashToken(req.ChallengeToken)
+
+	value, err := s.inMemory.Get(challengeKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return nil, status.Errorf(codes.Unauthenticated, "challenge_expired")
+		}
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	userID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	// the failures are counted per user, so a new challenge doesn't bring new guesses
+	err = s.checkLockout(ctx, attemptScopeTwoFactor, value)
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := s.storage.TwoFactor().Get(userID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get two factor settings")
+		return nil, status.Errorf(codes.Internal, "internal server error: %v", err)
+	}
+
+	ok, err := s.checkTwoFactorCode(userID, twoFactor.Secret, req.Code)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	if !ok {
+		s.registerFailedAttempt(ctx, attemptScopeTwoFactor, value)
+		return nil, s.registerWrongCode(challengeKey, status.Errorf(codes.Unauthenticated, "incorrect_code"))
+	}
+
+	s.resetFailedAttempts(attemptScopeTwoFactor, value)
+
+	err = s.inMemory.Del(challengeKey)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to delete two factor challenge")
+	}
+
+	err = s.inMemory.Del(CodeAttemptsKey + challengeKey)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to delete two factor challenge attempts")
+	}
+
+	user, err := s.storage.User().Get(userID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get user")
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "internal server error: %v", err)
+	}
+
+	err = checkUserStatus(user)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.createAuthResponse(ctx, user, "")
+	if err != nil {
+		s.logger.WithError(err).Error("failed to create token")
+		return nil, status.Errorf(codes.Internal, "internal error: %v", err)
+	}
+
+	return response, nil
+}
+
+// createTwoFactorChallenge returns a short-lived token proving that the password check passed
+func (s *AuthService) createTwoFactorChallenge(userID int64) (string, error) {
+	token, err := utils.GenerateRandomToken(challengeTokenByteLength)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.inMemory.Set(TwoFactorChallengeKey+utils.HashToken(token), strconv.FormatInt(userID, 10), twoFactorChallengeTTL)
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// checkTwoFactorCode accepts either a TOTP code, which can be used only once,
+// or one of the user's unused recovery codes
+func (s *AuthService) checkTwoFactorCode(userID int64, secret, code string) (bool, error) {
+	if utils.ValidateTOTPCode(secret, code, time.Now()) {
+		return s.inMemory.SetNX(fmt.Sprintf("%s%d_%s", TOTPUsedCodeKey, userID, code), "1", 3*time.Minute)
+	}
+
+	return s.storage.TwoFactor().UseRecoveryCode(userID, utils.HashToken(code))
+}
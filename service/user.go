@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/TemurMannonov/medium_user_service/config"
 	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
 	"github.com/TemurMannonov/medium_user_service/storage/repo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -20,39 +23,77 @@ type UserService struct {
 	pb.UnimplementedUserServiceServer
 	storage  storage.StorageI
 	inMemory storage.InMemoryStorageI
+	auth     *AuthService
+	cfg      *config.Config
 	logger   *logrus.Logger
 }
 
-func NewUserService(strg storage.StorageI, inMemory storage.InMemoryStorageI, logger *logrus.Logger) *UserService {
+func NewUserService(strg storage.StorageI, inMemory storage.InMemoryStorageI, auth *AuthService, cfg *config.Config, logger *logrus.Logger) *UserService {
 	return &UserService{
 		storage:  strg,
 		inMemory: inMemory,
+		auth:     auth,
+		cfg:      cfg,
 		logger:   logger,
 	}
 }
 
 func (s *UserService) Create(ctx context.Context, req *pb.User) (*pb.User, error) {
 	s.logger.Info("create user")
+	caller, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the users sign up with Register, only superadmins and internal callers create them directly
+	superadmin := isSuperadmin(caller)
+	if !superadmin && !isInternalCall(ctx, s.cfg.InternalServiceToken) {
+		return nil, status.Errorf(codes.PermissionDenied, "only superadmins and internal callers can create users")
+	}
+
+	userType := req.Type
+	switch userType {
+	case "":
+		userType = repo.UserTypeUser
+	case repo.UserTypeUser:
+	case repo.UserTypeSuperadmin:
+		if !superadmin {
+			return nil, status.Errorf(codes.PermissionDenied, "only superadmins can create superadmins")
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown user type: %s", req.Type)
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to hash: %v", err)
+	}
+
 	user, err := s.storage.User().Create(&repo.User{
 		FirstName:       req.FirstName,
 		LastName:        req.LastName,
 		PhoneNumber:     req.PhoneNumber,
 		Email:           req.Email,
 		Gender:          req.Gender,
-		Password:        req.Password,
+		Password:        hashedPassword,
 		Username:        req.Username,
 		ProfileImageUrl: req.ProfileImageUrl,
-		Type:            req.Type,
+		Type:            userType,
 	})
 	if err != nil {
 		s.logger.WithError(err).Error("failed to create user")
-		return nil, status.Errorf(codes.Internal, "failed to create: %v", err)
+		return nil, createUserError(err)
 	}
 
-	return parseUserModel(user), nil
+	return parseUserModel(user, viewFor(caller, user.ID)), nil
 }
 
 func (s *UserService) Get(ctx context.Context, req *pb.IdRequest) (*pb.User, error) {
+	caller, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.storage.User().Get(req.Id)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to get user")
@@ -62,10 +103,15 @@ func (s *UserService) Get(ctx context.Context, req *pb.IdRequest) (*pb.User, err
 		return nil, status.Errorf(codes.Internal, "failed to get: %v", err)
 	}
 
-	return parseUserModel(user), nil
+	return parseUserModel(user, viewFor(caller, user.ID)), nil
 }
 
+// GetByEmail is served to internal callers only, since it allows to enumerate the accounts
 func (s *UserService) GetByEmail(ctx context.Context, req *pb.GetByEmailRequest) (*pb.User, error) {
+	if !isInternalCall(ctx, s.cfg.InternalServiceToken) {
+		return nil, status.Errorf(codes.PermissionDenied, "internal callers only")
+	}
+
 	user, err := s.storage.User().GetByEmail(req.Email)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to get user by email")
@@ -75,15 +121,31 @@ func (s *UserService) GetByEmail(ctx context.Context, req *pb.GetByEmailRequest)
 		return nil, status.Errorf(codes.Internal, "failed to get by email: %v", err)
 	}
 
-	return parseUserModel(user), nil
+	return parseUserModel(user, userViewAdmin), nil
 }
 
 func (s *UserService) GetAll(ctx context.Context, req *pb.GetAllUsersRequest) (*pb.GetAllUsersResponse, error) {
-	result, err := s.storage.User().GetAll(&repo.GetAllUsersParams{
-		Limit:  req.Limit,
-		Page:   req.Page,
-		Search: req.Search,
-	})
+	caller, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := getAllUsersParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// the email isn't public, only the callers seeing it can search by it
+	params.SearchEmail = isSuperadmin(caller) || isInternalCall(ctx, s.cfg.InternalServiceToken)
+
+	// only superadmins see the users that aren't active
+	if !isSuperadmin(caller) {
+		params.Status = repo.UserStatusActive
+	} else if params.Status != "" && !isValidUserStatus(params.Status) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown status: %s", params.Status)
+	}
+
+	result, err := s.storage.User().GetAll(params)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to get all user")
 		return nil, status.Errorf(codes.Internal, "failed to get all users: %v", err)
@@ -94,48 +156,227 @@ func (s *UserService) GetAll(ctx context.Context, req *pb.GetAllUsersRequest) (*
 		Users: make([]*pb.User, 0),
 	}
 
+	if result.NextCursor != nil {
+		response.NextCursor, err = utils.EncodeCursor(result.NextCursor)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to encode cursor")
+			return nil, status.Errorf(codes.Internal, "failed to get all users: %v", err)
+		}
+	}
+
 	for _, user := range result.Users {
-		response.Users = append(response.Users, parseUserModel(user))
+		response.Users = append(response.Users, parseUserModel(user, viewFor(caller, user.ID)))
 	}
 
 	return &response, nil
 }
 
-func parseUserModel(user *repo.User) *pb.User {
-	return &pb.User{
-		Id:              user.ID,
-		FirstName:       user.FirstName,
-		LastName:        user.LastName,
-		PhoneNumber:     user.PhoneNumber,
-		Email:           user.Email,
-		Gender:          user.Gender,
-		Password:        user.Password,
-		Username:        user.Username,
-		ProfileImageUrl: user.ProfileImageUrl,
-		Type:            user.Type,
-		CreatedAt:       user.CreatedAt.Format(time.RFC3339),
+func (s *UserService) Update(ctx context.Context, req *pb.UpdateUserRequest) (*pb.User, error) {
+	if req.User == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "user is required")
+	}
+
+	caller, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := userUpdateFields(req)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (s *UserService) Update(ctx context.Context, req *pb.User) (*pb.User, error) {
 	user, err := s.storage.User().Update(&repo.User{
-		ID:              req.Id,
-		FirstName:       req.FirstName,
-		LastName:        req.LastName,
-		PhoneNumber:     req.PhoneNumber,
-		Gender:          req.Gender,
-		Username:        req.Username,
-		ProfileImageUrl: req.ProfileImageUrl,
-	})
+		ID:              req.User.Id,
+		FirstName:       req.User.FirstName,
+		LastName:        req.User.LastName,
+		PhoneNumber:     req.User.PhoneNumber,
+		Gender:          req.User.Gender,
+		Username:        req.User.Username,
+		ProfileImageUrl: req.User.ProfileImageUrl,
+	}, fields)
 	if err != nil {
 		s.logger.WithError(err).Error("failed to update user")
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, status.Errorf(codes.NotFound, err.Error())
+		return nil, databaseError(err, "failed to update")
+	}
+
+	return parseUserModel(user, viewFor(caller, user.ID)), nil
+}
+
+func (s *UserService) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "query is required")
+	}
+
+	caller, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	} else if limit > 50 {
+		limit = 50
+	}
+
+	params := repo.SearchUsersParams{
+		Query:       req.Query,
+		Limit:       limit,
+		SearchEmail: isSuperadmin(caller) || isInternalCall(ctx, s.cfg.InternalServiceToken),
+	}
+	if !isSuperadmin(caller) {
+		params.Status = repo.UserStatusActive
+	}
+
+	results, err := s.storage.User().Search(&params)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to search users")
+		return nil, status.Errorf(codes.Internal, "failed to search users: %v", err)
+	}
+
+	response := pb.SearchUsersResponse{
+		Results: make([]*pb.UserSearchResult, 0, len(results)),
+	}
+
+	for _, r := range results {
+		response.Results = append(response.Results, &pb.UserSearchResult{
+			User:    parseUserModel(r.User, viewFor(caller, r.User.ID)),
+			Snippet: r.Snippet,
+			Rank:    r.Rank,
+		})
+	}
+
+	return &response, nil
+}
+
+// getAllUsersParams validates the filters and the sorting of the request
+func getAllUsersParams(req *pb.GetAllUsersRequest) (*repo.GetAllUsersParams, error) {
+	params := repo.GetAllUsersParams{
+		Limit:     req.Limit,
+		Page:      req.Page,
+		Search:    req.Search,
+		Type:      req.Type,
+		Gender:    req.Gender,
+		Status:    req.Status,
+		SortBy:    req.SortBy,
+		SortOrder: strings.ToLower(req.SortOrder),
+		WithCount: req.WithCount,
+	}
+
+	if params.Limit <= 0 {
+		params.Limit = 10
+	} else if params.Limit > 100 {
+		params.Limit = 100
+	}
+
+	var err error
+	if params.Page < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "page can't be negative")
+	}
+
+	if req.Cursor != "" {
+		if params.Page > 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "cursor can't be combined with page")
 		}
-		return nil, status.Errorf(codes.Internal, "failed to update: %v", err)
+
+		var after repo.UserCursor
+		err = utils.DecodeCursor(req.Cursor, &after)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+		}
+		params.After = &after
+	}
+	if req.CreatedFrom != "" {
+		params.CreatedFrom, err = time.Parse(time.RFC3339, req.CreatedFrom)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid created_from: %v", err)
+		}
+	}
+
+	if req.CreatedTo != "" {
+		params.CreatedTo, err = time.Parse(time.RFC3339, req.CreatedTo)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid created_to: %v", err)
+		}
+	}
+
+	if req.HasUsername != nil {
+		params.HasUsername = &req.HasUsername.Value
+	}
+
+	switch params.SortBy {
+	case "":
+		params.SortBy = repo.UserSortCreatedAt
+	case repo.UserSortCreatedAt, repo.UserSortFirstName, repo.UserSortLastName, repo.UserSortUsername:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown sort_by: %s", req.SortBy)
+	}
+
+	if params.Page == 0 && params.SortBy != repo.UserSortCreatedAt {
+		return nil, status.Errorf(codes.InvalidArgument, "cursor pagination supports sorting by %s only", repo.UserSortCreatedAt)
+	}
+
+	switch params.SortOrder {
+	case "":
+		params.SortOrder = repo.SortOrderDesc
+	case repo.SortOrderAsc, repo.SortOrderDesc:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "sort_order must be asc or desc")
 	}
 
-	return parseUserModel(user), nil
+	return &params, nil
+}
+
+var userUpdatableFields = []string{
+	repo.UserFieldFirstName,
+	repo.UserFieldLastName,
+	repo.UserFieldPhoneNumber,
+	repo.UserFieldGender,
+	repo.UserFieldUsername,
+	repo.UserFieldProfileImageUrl,
+}
+
+// userUpdateFields returns the fields listed in the update mask. Without a mask the fields
+// set in the request are updated.
+func userUpdateFields(req *pb.UpdateUserRequest) ([]string, error) {
+	values := map[string]string{
+		repo.UserFieldFirstName:       req.User.FirstName,
+		repo.UserFieldLastName:        req.User.LastName,
+		repo.UserFieldPhoneNumber:     req.User.PhoneNumber,
+		repo.UserFieldGender:          req.User.Gender,
+		repo.UserFieldUsername:        req.User.Username,
+		repo.UserFieldProfileImageUrl: req.User.ProfileImageUrl,
+	}
+
+	paths := req.UpdateMask.GetPaths()
+	if len(paths) == 0 {
+		for _, field := range userUpdatableFields {
+			if values[field] != "" {
+				paths = append(paths, field)
+			}
+		}
+	}
+
+	fields := make([]string, 0, len(paths))
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		value, ok := values[path]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown update_mask path: %s", path)
+		}
+
+		if value == "" && (path == repo.UserFieldFirstName || path == repo.UserFieldLastName) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s can't be empty", path)
+		}
+
+		if !seen[path] {
+			seen[path] = true
+			fields = append(fields, path)
+		}
+	}
+
+	return fields, nil
 }
 
 func (s *UserService) Delete(ctx context.Context, req *pb.IdRequest) (*emptypb.Empty, error) {
@@ -148,5 +389,30 @@ func (s *UserService) Delete(ctx context.Context, req *pb.IdRequest) (*emptypb.E
 		return nil, status.Errorf(codes.Internal, "failed to delete: %v", err)
 	}
 
+	err = s.auth.revokeUserTokens(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to revoke user tokens")
+	}
+
 	return &emptypb.Empty{}, nil
 }
+
+// Restore brings back a deleted user that hasn't been purged yet
+func (s *UserService) Restore(ctx context.Context, req *pb.IdRequest) (*pb.User, error) {
+	caller, err := s.requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if viewFor(caller, req.Id) != userViewAdmin {
+		return nil, status.Errorf(codes.PermissionDenied, "only superadmins can restore users")
+	}
+
+	user, err := s.storage.User().Restore(req.Id)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to restore user")
+		return nil, databaseError(err, "failed to restore")
+	}
+
+	return parseUserModel(user, userViewAdmin), nil
+}
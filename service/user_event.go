@@ -0,0 +1,109 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	userEventsBatchSize    = 100
+	userEventsPollInterval = time.Second
+)
+
+var userEventTypes = map[string]bool{
+	repo.EventUserCreated: true,
+	repo.EventUserUpdated: true,
+	repo.EventUserDeleted: true,
+}
+
+// WatchUserEvents replays the user event log after the requested sequence and then tails it,
+// until the client cancels the stream
+func (s *UserService) WatchUserEvents(req *pb.WatchUserEventsRequest, stream pb.UserService_WatchUserEventsServer) error {
+	ctx := stream.Context()
+
+	if !isInternalCall(ctx, s.cfg.InternalServiceToken) {
+		caller, err := s.requireCaller(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !isSuperadmin(caller) {
+			return status.Errorf(codes.PermissionDenied, "only internal callers and superadmins can watch user events")
+		}
+	}
+
+	if req.AfterSequence < 0 {
+		return status.Errorf(codes.InvalidArgument, "after_sequence can't be negative")
+	}
+
+	for _, t := range req.Types {
+		if !userEventTypes[t] {
+			return status.Errorf(codes.InvalidArgument, "unknown event type: %s", t)
+		}
+	}
+
+	params := repo.GetUserEventsParams{
+		AfterSequence: req.AfterSequence,
+		Types:         req.Types,
+		Limit:         userEventsBatchSize,
+	}
+
+	ticker := time.NewTicker(userEventsPollInterval)
+	defer ticker.Stop()
+
+	for {
+		events, err := s.storage.UserEvent().GetAfter(&params)
+		if errors.Is(err, sql.ErrNoRows) {
+			return status.Errorf(codes.InvalidArgument, "unknown after_sequence: %d", params.AfterSequence)
+		}
+		if err != nil {
+			s.logger.WithError(err).Error("failed to get user events")
+			return status.Errorf(codes.Internal, "failed to get user events: %v", err)
+		}
+
+		for _, e := range events {
+			err := stream.Send(parseUserEventModel(e))
+			if err != nil {
+				return err
+			}
+			params.AfterSequence = e.Sequence
+		}
+
+		// a full batch means the log may have more to replay right away
+		if len(events) == userEventsBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func parseUserEventModel(e *repo.UserEvent) *pb.UserEvent {
+	return &pb.UserEvent{
+		Sequence:   e.Sequence,
+		Id:         e.EventID,
+		Type:       e.Type,
+		UserId:     e.UserID,
+		OccurredAt: e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		User: &pb.User{
+			Id:        e.Payload.ID,
+			FirstName: e.Payload.FirstName,
+			LastName:  e.Payload.LastName,
+			Email:     e.Payload.Email,
+			Username:  e.Payload.Username,
+			Type:      e.Payload.Type,
+			Status:    e.Payload.Status,
+		},
+		Fields: e.Payload.Fields,
+	}
+}
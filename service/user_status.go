@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func isValidUserStatus(s string) bool {
+	switch s {
+	case repo.UserStatusActive,
+		repo.UserStatusPending,
+		repo.UserStatusSuspended,
+		repo.UserStatusBanned,
+		repo.UserStatusDeactivated:
+		return true
+	}
+	return false
+}
+
+// UpdateStatus lets superadmins suspend, ban, deactivate or reactivate a user. The tokens
+// of a user leaving the active status are revoked, so they stop passing VerifyToken at once.
+func (s *UserService) UpdateStatus(ctx context.Context, req *pb.UpdateUserStatusRequest) (*pb.User, error) {
+	caller, err := s.requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSuperadmin(caller) {
+		return nil, status.Errorf(codes.PermissionDenied, "only superadmins can change the status of users")
+	}
+
+	if !isValidUserStatus(req.Status) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown status: %s", req.Status)
+	}
+
+	if caller.UserId == req.UserId {
+		return nil, status.Errorf(codes.FailedPrecondition, "superadmins can't change their own status")
+	}
+
+	params := repo.UpdateUserStatus{
+		UserID:  req.UserId,
+		Status:  req.Status,
+		Reason:  req.Reason,
+		ActorID: caller.UserId,
+	}
+
+	if req.ExpiresAt != "" {
+		if req.Status == repo.UserStatusActive {
+			return nil, status.Errorf(codes.InvalidArgument, "expires_at can't be set for the active status")
+		}
+
+		params.ExpiresAt, err = time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid expires_at: %v", err)
+		}
+
+		if !params.ExpiresAt.After(time.Now()) {
+			return nil, status.Errorf(codes.InvalidArgument, "expires_at must be in the future")
+		}
+	}
+
+	user, err := s.storage.User().UpdateStatus(&params)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to update user status")
+		return nil, databaseError(err, "failed to update status")
+	}
+
+	if !user.IsActive() {
+		err = s.auth.revokeUserTokens(user.ID)
+		if err != nil {
+			s.logger.WithError(err).Error("failed to revoke user tokens")
+			return nil, status.Errorf(codes.Internal, "failed to revoke user tokens: %v", err)
+		}
+	}
+
+	return parseUserModel(user, userViewAdmin), nil
+}
+
+// checkUserStatus refuses the users that aren't active from getting tokens
+func checkUserStatus(user *repo.User) error {
+	if user.IsActive() {
+		return nil
+	}
+
+	if !user.StatusExpiresAt.IsZero() {
+		return status.Errorf(codes.PermissionDenied, "account is %s until %s", user.Status, user.StatusExpiresAt.Format(time.RFC3339))
+	}
+
+	return status.Errorf(codes.PermissionDenied, "account is %s", user.Status)
+}
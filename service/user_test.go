@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCreateUserAuthorization(t *testing.T) {
+	auth, inMemory := newTestAuthService(t)
+	auth.cfg.InternalServiceToken = "internal_token"
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	users := NewUserService(auth.storage, inMemory, auth, auth.cfg, logger)
+
+	req := &pb.User{Email: "user@example.com", Password: "password", Type: repo.UserTypeSuperadmin}
+
+	_, err := users.Create(context.Background(), req)
+	requireCode(t, err, codes.PermissionDenied)
+
+	internal := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-internal-token", "internal_token"))
+	_, err = users.Create(internal, req)
+	requireCode(t, err, codes.PermissionDenied)
+
+	req.Type = "moderator"
+	_, err = users.Create(internal, req)
+	requireCode(t, err, codes.InvalidArgument)
+}
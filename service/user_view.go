@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// userView selects the fields of a user returned to the caller
+type userView int
+
+const (
+	// userViewPublic is the profile anyone can see
+	userViewPublic userView = iota
+	// userViewSelf adds the contact details and the status, returned to the user themselves
+	userViewSelf
+	// userViewAdmin adds the account type and the moderator, returned to superadmins and internal callers
+	userViewAdmin
+)
+
+// caller returns the payload of the caller's token, or nil for anonymous calls
+func (s *UserService) caller(ctx context.Context) (*pb.AuthPayload, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get("authorization")) == 0 {
+		return nil, nil
+	}
+
+	token, err := accessTokenFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, _, err := s.auth.authenticate(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// requireCaller is caller for the rpcs acting on behalf of the user
+func (s *UserService) requireCaller(ctx context.Context) (*pb.AuthPayload, error) {
+	caller, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if caller == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "authorization metadata is not provided")
+	}
+
+	return caller, nil
+}
+
+// viewFor returns the view of the user with the given id that the caller is allowed to see
+func viewFor(caller *pb.AuthPayload, userID int64) userView {
+	if caller == nil {
+		return userViewPublic
+	}
+
+	if isSuperadmin(caller) {
+		return userViewAdmin
+	}
+
+	if caller.UserId == userID {
+		return userViewSelf
+	}
+
+	return userViewPublic
+}
+
+func isSuperadmin(caller *pb.AuthPayload) bool {
+	if caller == nil {
+		return false
+	}
+
+	for _, role := range caller.Roles {
+		if role == repo.UserTypeSuperadmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseUserModel never copies the password hash, whatever the view is
+func parseUserModel(user *repo.User, view userView) *pb.User {
+	result := pb.User{
+		Id:              user.ID,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Username:        user.Username,
+		ProfileImageUrl: user.ProfileImageUrl,
+		CreatedAt:       user.CreatedAt.Format(time.RFC3339),
+		FollowersCount:  user.FollowersCount,
+		FollowingCount:  user.FollowingCount,
+	}
+
+	if view >= userViewSelf {
+		result.Email = user.Email
+		result.PhoneNumber = user.PhoneNumber
+		result.Gender = user.Gender
+		result.Status = user.Status
+		result.StatusReason = user.StatusReason
+		if !user.StatusExpiresAt.IsZero() {
+			result.StatusExpiresAt = user.StatusExpiresAt.Format(time.RFC3339)
+		}
+	}
+
+	if view >= userViewAdmin {
+		result.Type = user.Type
+		result.StatusChangedBy = user.StatusChangedBy
+	}
+
+	return &result
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	pb "github.com/TemurMannonov/medium_user_service/genproto/user_service"
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/pkg/webhook"
+	"github.com/TemurMannonov/medium_user_service/storage"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	WebhookSecretPrefix = "whsec_"
+
+	webhookSecretByteLength = 32
+)
+
+type WebhookService struct {
+	pb.UnimplementedWebhookServiceServer
+	storage storage.StorageI
+	auth    *AuthService
+	logger  *logrus.Logger
+}
+
+func NewWebhookService(strg storage.StorageI, auth *AuthService, logger *logrus.Logger) *WebhookService {
+	return &WebhookService{
+		storage: strg,
+		auth:    auth,
+		logger:  logger,
+	}
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, req *pb.CreateWebhookRequest) (*pb.CreateWebhookResponse, error) {
+	payload, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(req.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "url must be an absolute http or https url")
+	}
+
+	err = webhook.ValidateURL(ctx, req.Url)
+	if errors.Is(err, webhook.ErrForbiddenAddress) {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to resolve the url host: %v", err)
+	}
+
+	if len(req.EventTypes) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "at least one event type is required")
+	}
+
+	for _, t := range req.EventTypes {
+		if !userEventTypes[t] {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown event type: %s", t)
+		}
+	}
+
+	random, err := utils.GenerateRandomToken(webhookSecretByteLength)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate webhook secret: %v", err)
+	}
+	secret := WebhookSecretPrefix + random
+
+	created, err := s.storage.Webhook().Create(&repo.Webhook{
+		URL:        req.Url,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Active:     true,
+	}, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to create webhook")
+		return nil, databaseError(err, "failed to create webhook")
+	}
+
+	return &pb.CreateWebhookResponse{
+		Webhook: parseWebhookModel(created),
+		Secret:  secret,
+	}, nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context, req *emptypb.Empty) (*pb.ListWebhooksResponse, error) {
+	_, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.storage.Webhook().GetAll()
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get all webhooks")
+		return nil, status.Errorf(codes.Internal, "failed to get all webhooks: %v", err)
+	}
+
+	response := pb.ListWebhooksResponse{
+		Webhooks: make([]*pb.Webhook, 0),
+	}
+
+	for _, w := range webhooks {
+		response.Webhooks = append(response.Webhooks, parseWebhookModel(w))
+	}
+
+	return &response, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, req *pb.IdRequest) (*emptypb.Empty, error) {
+	payload, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.storage.Webhook().Delete(req.Id, payload.UserID)
+	if err != nil {
+		s.logger.WithError(err).Error("failed to delete webhook")
+		return nil, databaseError(err, "failed to delete webhook")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *WebhookService) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	_, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Status {
+	case "", repo.WebhookDeliveryPending, repo.WebhookDeliverySucceeded, repo.WebhookDeliveryFailed:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown delivery status: %s", req.Status)
+	}
+
+	limit, page := req.Limit, req.Page
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	result, err := s.storage.Webhook().GetDeliveries(&repo.GetAllWebhookDeliveriesParams{
+		Limit:     limit,
+		Page:      page,
+		WebhookID: req.WebhookId,
+		Status:    req.Status,
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("failed to get webhook deliveries")
+		return nil, status.Errorf(codes.Internal, "failed to get webhook deliveries: %v", err)
+	}
+
+	response := pb.ListWebhookDeliveriesResponse{
+		Count:      result.Count,
+		Deliveries: make([]*pb.WebhookDelivery, 0),
+	}
+
+	for _, d := range result.Deliveries {
+		response.Deliveries = append(response.Deliveries, parseWebhookDeliveryModel(d))
+	}
+
+	return &response, nil
+}
+
+// ReplayWebhookDelivery sends the delivery again, whatever its status
+func (s *WebhookService) ReplayWebhookDelivery(ctx context.Context, req *pb.IdRequest) (*pb.WebhookDelivery, error) {
+	_, err := s.authorizeSuperadmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.storage.Webhook().ReplayDelivery(req.Id)
+	if errors.Is(err, repo.ErrDeliveryPending) {
+		return nil, status.Errorf(codes.FailedPrecondition, "only the delivered or failed deliveries can be replayed")
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("failed to replay webhook delivery")
+		return nil, databaseError(err, "failed to replay webhook delivery")
+	}
+
+	return parseWebhookDeliveryModel(delivery), nil
+}
+
+// authorizeSuperadmin verifies the access token from the metadata and allows only superadmins
+func (s *WebhookService) authorizeSuperadmin(ctx context.Context) (*utils.Payload, error) {
+	accessToken, err := accessTokenFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := s.auth.verifyAccessToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !payload.HasRole(repo.UserTypeSuperadmin) {
+		return nil, status.Errorf(codes.PermissionDenied, "only superadmins can manage webhooks")
+	}
+
+	return payload, nil
+}
+
+func parseWebhookModel(w *repo.Webhook) *pb.Webhook {
+	return &pb.Webhook{
+		Id:         w.ID,
+		Url:        w.URL,
+		EventTypes: w.EventTypes,
+		Active:     w.Active,
+		CreatedBy:  w.CreatedBy,
+		CreatedAt:  w.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func parseWebhookDeliveryModel(d *repo.WebhookDelivery) *pb.WebhookDelivery {
+	result := pb.WebhookDelivery{
+		Id:             d.ID,
+		WebhookId:      d.WebhookID,
+		EventId:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        string(d.Payload),
+		Status:         d.Status,
+		Attempts:       int32(d.Attempts),
+		ResponseStatus: int32(d.ResponseStatus),
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+	}
+
+	if d.Status == repo.WebhookDeliveryPending {
+		result.NextAttemptAt = d.NextAttemptAt.Format(time.RFC3339)
+	}
+
+	if !d.DeliveredAt.IsZero() {
+		result.DeliveredAt = d.DeliveredAt.Format(time.RFC3339)
+	}
+
+	return &result
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/config"
+	"github.com/TemurMannonov/medium_user_service/pkg/webhook"
+	"github.com/TemurMannonov/medium_user_service/storage"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/sirupsen/logrus"
+)
+
+const webhookBatchSize = 20
+
+// WebhookDispatcher sends the deliveries queued for the webhooks by the outbox dispatcher,
+// retrying the failed ones with the backoff of the outbox
+type WebhookDispatcher struct {
+	storage storage.StorageI
+	client  *webhook.Client
+	cfg     *config.Config
+	logger  *logrus.Logger
+}
+
+func NewWebhookDispatcher(strg storage.StorageI, cfg *config.Config, logger *logrus.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		storage: strg,
+		client:  webhook.NewClient(cfg.WebhookTimeout),
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// Run sends the due deliveries every poll interval, until ctx is done
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.OutboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *WebhookDispatcher) dispatch(ctx context.Context) {
+	for {
+		// the lease outlasts a batch of deliveries timing out one after another
+		lease := d.cfg.WebhookTimeout*webhookBatchSize + time.Minute
+
+		deliveries, err := d.storage.Webhook().ClaimDeliveries(webhookBatchSize, lease)
+		if err != nil {
+			d.logger.WithError(err).Error("failed to claim webhook deliveries")
+			return
+		}
+
+		for _, delivery := range deliveries {
+			d.deliver(ctx, delivery)
+		}
+
+		if len(deliveries) < webhookBatchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *repo.WebhookDelivery) {
+	logger := d.logger.WithFields(logrus.Fields{
+		"delivery_id": delivery.ID,
+		"webhook_id":  delivery.WebhookID,
+		"event_type":  delivery.EventType,
+	})
+
+	responseStatus, err := d.client.Send(ctx, &webhook.Request{
+		URL:        delivery.Webhook.URL,
+		Secret:     delivery.Webhook.Secret,
+		Event:      delivery.EventType,
+		DeliveryID: strconv.FormatInt(delivery.ID, 10),
+		Body:       delivery.Payload,
+	})
+	if err == nil {
+		err = d.storage.Webhook().MarkDelivered(delivery.ID, responseStatus)
+		if err != nil {
+			logger.WithError(err).Error("failed to mark webhook delivery as delivered")
+		}
+		return
+	}
+
+	dead := delivery.Attempts+1 >= d.cfg.WebhookMaxAttempts
+	if dead {
+		logger.WithError(err).Error("webhook delivery failed")
+	} else {
+		logger.WithError(err).Warn("failed to deliver webhook")
+	}
+
+	nextAttemptAt := time.Now().Add(outboxBackoff(delivery.Attempts))
+
+	err = d.storage.Webhook().MarkDeliveryFailed(delivery.ID, responseStatus, err.Error(), nextAttemptAt, dead)
+	if err != nil {
+		logger.WithError(err).Error("failed to mark webhook delivery as failed")
+	}
+}
@@ -2,14 +2,22 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-redis/redis/v9"
 )
 
+// ErrKeyNotFound is returned by Get when the key does not exist or has expired
+var ErrKeyNotFound = errors.New("key not found")
+
 type InMemoryStorageI interface {
 	Set(key, value string, exp time.Duration) error
 	Get(key string) (string, error)
+	Del(key string) error
+	SetNX(key, value string, exp time.Duration) (bool, error)
+	Incr(key string, exp time.Duration) (int64, error)
+	TTL(key string) (time.Duration, error)
 }
 
 type storageRedis struct {
@@ -33,7 +41,59 @@ func (r *storageRedis) Set(key, value string, exp time.Duration) error {
 func (r *storageRedis) Get(key string) (string, error) {
 	val, err := r.client.Get(context.Background(), key).Result()
 	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", ErrKeyNotFound
+		}
 		return "", err
 	}
 	return val, nil
 }
+
+func (r *storageRedis) Del(key string) error {
+	err := r.client.Del(context.Background(), key).Err()
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *storageRedis) SetNX(key, value string, exp time.Duration) (bool, error) {
+	ok, err := r.client.SetNX(context.Background(), key, value, exp).Result()
+	if err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+// Incr increments the counter and sets its expiration when the counter is created
+func (r *storageRedis) Incr(key string, exp time.Duration) (int64, error) {
+	ctx := context.Background()
+
+	val, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if val == 1 {
+		err = r.client.Expire(ctx, key, exp).Err()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return val, nil
+}
+
+func (r *storageRedis) TTL(key string) (time.Duration, error) {
+	ttl, err := r.client.TTL(context.Background(), key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	// redis returns negative values when the key does not exist or has no expiration
+	if ttl < 0 {
+		return 0, ErrKeyNotFound
+	}
+
+	return ttl, nil
+}
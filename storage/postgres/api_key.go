@@ -0,0 +1,238 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/jmoiron/sqlx"
+)
+
+type apiKeyRepo struct {
+	db *sqlx.DB
+}
+
+func NewApiKey(db *sqlx.DB) repo.ApiKeyStorageI {
+	return &apiKeyRepo{
+		db: db,
+	}
+}
+
+func (ar *apiKeyRepo) Create(key *repo.ApiKey) (*repo.ApiKey, error) {
+	tx, err := ar.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var expiresAt sql.NullTime
+	if !key.ExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: key.ExpiresAt, Valid: true}
+	}
+
+	query := `
+		INSERT INTO api_keys(
+			user_id,
+			name,
+			prefix,
+			key_hash,
+			expires_at
+		) VALUES($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(
+		query,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		expiresAt,
+	).Scan(
+		&key.ID,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range key.Scopes {
+		_, err = tx.Exec(
+			`INSERT INTO api_key_scopes(api_key_id, resource, action) VALUES($1, $2, $3)`,
+			key.ID, scope.Resource, scope.Action,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (ar *apiKeyRepo) GetByHash(keyHash string) (*repo.ApiKey, error) {
+	var (
+		result                repo.ApiKey
+		expiresAt, lastUsedAt sql.NullTime
+	)
+
+	query := `
+		SELECT
+			id,
+			user_id,
+			name,
+			prefix,
+			key_hash,
+			expires_at,
+			last_used_at,
+			created_at
+		FROM api_keys
+		WHERE key_hash=$1 AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+	`
+
+	err := ar.db.QueryRow(query, keyHash).Scan(
+		&result.ID,
+		&result.UserID,
+		&result.Name,
+		&result.Prefix,
+		&result.KeyHash,
+		&expiresAt,
+		&lastUsedAt,
+		&result.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ExpiresAt = expiresAt.Time
+	result.LastUsedAt = lastUsedAt.Time
+
+	result.Scopes, err = ar.getScopes(result.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (ar *apiKeyRepo) GetAllByUser(userID int64) ([]*repo.ApiKey, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			name,
+			prefix,
+			key_hash,
+			expires_at,
+			last_used_at,
+			created_at
+		FROM api_keys
+		WHERE user_id=$1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := ar.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.ApiKey, 0)
+	for rows.Next() {
+		var (
+			k                     repo.ApiKey
+			expiresAt, lastUsedAt sql.NullTime
+		)
+
+		err := rows.Scan(
+			&k.ID,
+			&k.UserID,
+			&k.Name,
+			&k.Prefix,
+			&k.KeyHash,
+			&expiresAt,
+			&lastUsedAt,
+			&k.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		k.ExpiresAt = expiresAt.Time
+		k.LastUsedAt = lastUsedAt.Time
+
+		result = append(result, &k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, k := range result {
+		k.Scopes, err = ar.getScopes(k.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (ar *apiKeyRepo) getScopes(apiKeyID int64) ([]*repo.ApiKeyScope, error) {
+	query := `SELECT resource, action FROM api_key_scopes WHERE api_key_id=$1 ORDER BY resource, action`
+
+	rows, err := ar.db.Query(query, apiKeyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.ApiKeyScope, 0)
+	for rows.Next() {
+		var scope repo.ApiKeyScope
+
+		err := rows.Scan(&scope.Resource, &scope.Action)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &scope)
+	}
+
+	return result, rows.Err()
+}
+
+func (ar *apiKeyRepo) UpdateLastUsed(id int64) error {
+	query := `UPDATE api_keys SET last_used_at=CURRENT_TIMESTAMP WHERE id=$1`
+
+	_, err := ar.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ar *apiKeyRepo) Revoke(id, userID int64) error {
+	query := `UPDATE api_keys SET revoked_at=CURRENT_TIMESTAMP WHERE id=$1 AND user_id=$2 AND revoked_at IS NULL`
+
+	result, err := ar.db.Exec(query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (ar *apiKeyRepo) RevokeAllByUser(userID int64) error {
+	_, err := ar.db.Exec(`UPDATE api_keys SET revoked_at=CURRENT_TIMESTAMP WHERE user_id=$1 AND revoked_at IS NULL`, userID)
+	return err
+}
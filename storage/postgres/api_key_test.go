@@ -0,0 +1,59 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/bxcodec/faker/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApiKey(t *testing.T) {
+	u := createUser(t)
+
+	k, err := strg.ApiKey().Create(&repo.ApiKey{
+		UserID:  u.ID,
+		Name:    faker.Word(),
+		Prefix:  "pat_abcdefgh",
+		KeyHash: faker.UUIDDigit(),
+		Scopes: []*repo.ApiKeyScope{
+			{Resource: "posts", Action: "create"},
+		},
+	})
+	require.NoError(t, err)
+
+	result, err := strg.ApiKey().GetByHash(k.KeyHash)
+	require.NoError(t, err)
+	require.True(t, result.HasScope("posts", "create"))
+	require.False(t, result.HasScope("posts", "update"))
+
+	err = strg.ApiKey().Revoke(k.ID, u.ID)
+	require.NoError(t, err)
+
+	_, err = strg.ApiKey().GetByHash(k.KeyHash)
+	require.Error(t, err)
+}
+
+func TestRevokeAllApiKeys(t *testing.T) {
+	u := createUser(t)
+
+	var hashes []string
+	for i := 0; i < 2; i++ {
+		k, err := strg.ApiKey().Create(&repo.ApiKey{
+			UserID:  u.ID,
+			Name:    faker.Word(),
+			Prefix:  "pat_abcdefgh",
+			KeyHash: faker.UUIDDigit(),
+		})
+		require.NoError(t, err)
+		hashes = append(hashes, k.KeyHash)
+	}
+
+	err := strg.ApiKey().RevokeAllByUser(u.ID)
+	require.NoError(t, err)
+
+	for _, hash := range hashes {
+		_, err = strg.ApiKey().GetByHash(hash)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/jmoiron/sqlx"
+)
+
+type auditLogRepo struct {
+	db *sqlx.DB
+}
+
+func NewAuditLog(db *sqlx.DB) repo.AuditLogStorageI {
+	return &auditLogRepo{
+		db: db,
+	}
+}
+
+// insertAuditLog writes the change inside the transaction that makes it,
+// so a change can't be committed without its audit record
+func insertAuditLog(tx *sql.Tx, actorID int64, action, entity string, entityID int64, oldValue, newValue interface{}) error {
+	var oldJSON, newJSON sql.NullString
+
+	if oldValue != nil {
+		b, err := json.Marshal(oldValue)
+		if err != nil {
+			return err
+		}
+		oldJSON = utils.NullString(string(b))
+	}
+
+	if newValue != nil {
+		b, err := json.Marshal(newValue)
+		if err != nil {
+			return err
+		}
+		newJSON = utils.NullString(string(b))
+	}
+
+	query := `
+		INSERT INTO audit_logs(
+			actor_id,
+			action,
+			entity,
+			entity_id,
+			old_value,
+			new_value
+		) VALUES($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := tx.Exec(
+		query,
+		actorID,
+		action,
+		entity,
+		entityID,
+		oldJSON,
+		newJSON,
+	)
+	return err
+}
+
+func (ar *auditLogRepo) GetAll(params *repo.GetAllAuditLogsParams) (*repo.GetAllAuditLogsResult, error) {
+	result := repo.GetAllAuditLogsResult{
+		AuditLogs: make([]*repo.AuditLog, 0),
+	}
+
+	var (
+		filter = " WHERE true "
+		args   = make([]interface{}, 0)
+	)
+
+	if params.Entity != "" {
+		args = append(args, params.Entity)
+		filter += fmt.Sprintf(" AND entity=$%d ", len(args))
+	}
+
+	if params.EntityID != 0 {
+		args = append(args, params.EntityID)
+		filter += fmt.Sprintf(" AND entity_id=$%d ", len(args))
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	limit := fmt.Sprintf(" LIMIT %d OFFSET %d ", params.Limit, offset)
+
+	query := `
+		SELECT
+			id,
+			actor_id,
+			action,
+			entity,
+			entity_id,
+			old_value,
+			new_value,
+			created_at
+		FROM audit_logs
+		` + filter + `
+		ORDER BY created_at DESC, id DESC
+		` + limit
+
+	rows, err := ar.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			l                  repo.AuditLog
+			actorID            sql.NullInt64
+			oldValue, newValue sql.NullString
+		)
+
+		err := rows.Scan(
+			&l.ID,
+			&actorID,
+			&l.Action,
+			&l.Entity,
+			&l.EntityID,
+			&oldValue,
+			&newValue,
+			&l.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		l.ActorID = actorID.Int64
+		l.OldValue = oldValue.String
+		l.NewValue = newValue.String
+
+		result.AuditLogs = append(result.AuditLogs, &l)
+	}
+
+	queryCount := `SELECT count(1) FROM audit_logs ` + filter
+	err = ar.db.QueryRow(queryCount, args...).Scan(&result.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
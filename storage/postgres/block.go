@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/jmoiron/sqlx"
+)
+
+type blockRepo struct {
+	db *sqlx.DB
+}
+
+func NewBlock(db *sqlx.DB) repo.BlockStorageI {
+	return &blockRepo{
+		db: db,
+	}
+}
+
+func (br *blockRepo) Block(blockerID, blockedID int64) (bool, error) {
+	tx, err := br.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	err = lockUserPair(tx, blockerID, blockedID)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO blocks(blocker_id, blocked_id) VALUES($1, $2) ON CONFLICT DO NOTHING`,
+		blockerID,
+		blockedID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return false, nil
+	}
+
+	for _, pair := range [][2]int64{{blockerID, blockedID}, {blockedID, blockerID}} {
+		result, err := tx.Exec(
+			`DELETE FROM follows WHERE follower_id=$1 AND followee_id=$2`,
+			pair[0],
+			pair[1],
+		)
+		if err != nil {
+			return false, err
+		}
+
+		if count, _ := result.RowsAffected(); count > 0 {
+			err = updateFollowCounts(tx, pair[0], pair[1], -1)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+func (br *blockRepo) Unblock(blockerID, blockedID int64) (bool, error) {
+	result, err := br.db.Exec(
+		`DELETE FROM blocks WHERE blocker_id=$1 AND blocked_id=$2`,
+		blockerID,
+		blockedID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	count, _ := result.RowsAffected()
+	return count > 0, nil
+}
+
+func (br *blockRepo) GetBlocked(params *repo.ListRelatedUsersParams) (*repo.ListRelatedUsersResult, error) {
+	return listRelatedUsers(br.db, params, "blocks", "blocked_id", "blocker_id")
+}
+
+func (br *blockRepo) Mute(muterID, mutedID int64) (bool, error) {
+	result, err := br.db.Exec(
+		`INSERT INTO mutes(muter_id, muted_id) VALUES($1, $2) ON CONFLICT DO NOTHING`,
+		muterID,
+		mutedID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	count, _ := result.RowsAffected()
+	return count > 0, nil
+}
+
+func (br *blockRepo) Unmute(muterID, mutedID int64) (bool, error) {
+	result, err := br.db.Exec(
+		`DELETE FROM mutes WHERE muter_id=$1 AND muted_id=$2`,
+		muterID,
+		mutedID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	count, _ := result.RowsAffected()
+	return count > 0, nil
+}
+
+func (br *blockRepo) GetMuted(params *repo.ListRelatedUsersParams) (*repo.ListRelatedUsersResult, error) {
+	return listRelatedUsers(br.db, params, "mutes", "muted_id", "muter_id")
+}
+
+func (br *blockRepo) GetInteraction(actorID, targetID int64) (*repo.Interaction, error) {
+	var result repo.Interaction
+
+	query := `
+		SELECT
+			EXISTS(SELECT 1 FROM blocks WHERE blocker_id=$1 AND blocked_id=$2),
+			EXISTS(SELECT 1 FROM blocks WHERE blocker_id=$2 AND blocked_id=$1),
+			EXISTS(SELECT 1 FROM mutes WHERE muter_id=$1 AND muted_id=$2),
+			EXISTS(SELECT 1 FROM mutes WHERE muter_id=$2 AND muted_id=$1)
+	`
+
+	err := br.db.QueryRow(query, actorID, targetID).Scan(
+		&result.Blocking,
+		&result.BlockedBy,
+		&result.Muting,
+		&result.MutedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
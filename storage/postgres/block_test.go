@@ -0,0 +1,61 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlock(t *testing.T) {
+	blocker := createUser(t)
+	blocked := createUser(t)
+
+	_, err := strg.Follow().Follow(blocker.ID, blocked.ID)
+	require.NoError(t, err)
+	_, err = strg.Follow().Follow(blocked.ID, blocker.ID)
+	require.NoError(t, err)
+
+	created, err := strg.Block().Block(blocker.ID, blocked.ID)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	following, err := strg.Follow().IsFollowing(blocked.ID, blocker.ID)
+	require.NoError(t, err)
+	require.False(t, following)
+
+	u, err := strg.User().Get(blocker.ID)
+	require.NoError(t, err)
+	require.Zero(t, u.FollowersCount)
+	require.Zero(t, u.FollowingCount)
+
+	created, err = strg.Follow().Follow(blocked.ID, blocker.ID)
+	require.NoError(t, err)
+	require.False(t, created)
+
+	interaction, err := strg.Block().GetInteraction(blocked.ID, blocker.ID)
+	require.NoError(t, err)
+	require.True(t, interaction.BlockedBy)
+	require.False(t, interaction.Blocking)
+
+	removed, err := strg.Block().Unblock(blocker.ID, blocked.ID)
+	require.NoError(t, err)
+	require.True(t, removed)
+}
+
+func TestMute(t *testing.T) {
+	muter := createUser(t)
+	muted := createUser(t)
+
+	created, err := strg.Block().Mute(muter.ID, muted.ID)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	interaction, err := strg.Block().GetInteraction(muted.ID, muter.ID)
+	require.NoError(t, err)
+	require.True(t, interaction.MutedBy)
+	require.False(t, interaction.BlockedBy)
+
+	removed, err := strg.Block().Unmute(muter.ID, muted.ID)
+	require.NoError(t, err)
+	require.True(t, removed)
+}
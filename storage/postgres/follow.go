@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/jmoiron/sqlx"
+)
+
+type followRepo struct {
+	db *sqlx.DB
+}
+
+func NewFollow(db *sqlx.DB) repo.FollowStorageI {
+	return &followRepo{
+		db: db,
+	}
+}
+
+// Follow inserts the follow and bumps the counts of both users in one transaction
+func (fr *followRepo) Follow(followerID, followeeID int64) (bool, error) {
+	tx, err := fr.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	err = lockUserPair(tx, followerID, followeeID)
+	if err != nil {
+		return false, err
+	}
+
+	// users blocked either way can't follow each other
+	result, err := tx.Exec(`
+		INSERT INTO follows(follower_id, followee_id)
+		SELECT $1, $2
+		WHERE NOT EXISTS(
+			SELECT 1 FROM blocks
+			WHERE (blocker_id=$1 AND blocked_id=$2) OR (blocker_id=$2 AND blocked_id=$1)
+		)
+		ON CONFLICT DO NOTHING`,
+		followerID,
+		followeeID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return false, nil
+	}
+
+	err = updateFollowCounts(tx, followerID, followeeID, 1)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func (fr *followRepo) Unfollow(followerID, followeeID int64) (bool, error) {
+	tx, err := fr.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	err = lockUserPair(tx, followerID, followeeID)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec(
+		`DELETE FROM follows WHERE follower_id=$1 AND followee_id=$2`,
+		followerID,
+		followeeID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return false, nil
+	}
+
+	err = updateFollowCounts(tx, followerID, followeeID, -1)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// lockUserPair locks both users in the id order until the transaction ends. Follow and Block
+// take it first, so a follow can't commit next to a block that missed it.
+func lockUserPair(tx *sql.Tx, firstID, secondID int64) error {
+	_, err := tx.Exec(
+		`SELECT id FROM users WHERE id IN ($1, $2) ORDER BY id FOR NO KEY UPDATE`,
+		firstID,
+		secondID,
+	)
+	return err
+}
+
+// updateFollowCounts moves the following count of the follower and the followers count of
+// the followee by delta, updating the users in the id order. It doesn't lock them itself, the
+// callers hold lockUserPair. A side is left alone when the follows of the user on the other
+// side aren't counted, see SyncFollowCounts.
+func updateFollowCounts(tx *sql.Tx, followerID, followeeID int64, delta int) error {
+	const counted = `EXISTS(SELECT 1 FROM users WHERE id=$3 AND follows_counted)`
+
+	following := func() error {
+		_, err := tx.Exec(`UPDATE users SET following_count=following_count+$1 WHERE id=$2 AND `+counted, delta, followerID, followeeID)
+		return err
+	}
+	followers := func() error {
+		_, err := tx.Exec(`UPDATE users SET followers_count=followers_count+$1 WHERE id=$2 AND `+counted, delta, followeeID, followerID)
+		return err
+	}
+
+	first, second := following, followers
+	if followeeID < followerID {
+		first, second = followers, following
+	}
+
+	err := first()
+	if err != nil {
+		return err
+	}
+
+	return second()
+}
+
+func (fr *followRepo) IsFollowing(followerID, followeeID int64) (bool, error) {
+	var exists bool
+	err := fr.db.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM follows WHERE follower_id=$1 AND followee_id=$2)`,
+		followerID,
+		followeeID,
+	).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (fr *followRepo) GetFollowers(params *repo.ListRelatedUsersParams) (*repo.ListRelatedUsersResult, error) {
+	return listRelatedUsers(fr.db, params, "follows", "follower_id", "followee_id")
+}
+
+func (fr *followRepo) GetFollowing(params *repo.ListRelatedUsersParams) (*repo.ListRelatedUsersResult, error) {
+	return listRelatedUsers(fr.db, params, "follows", "followee_id", "follower_id")
+}
+
+// listRelatedUsers returns the users in userColumn of the relation table rows having
+// params.UserID in ownerColumn, the latest first
+func listRelatedUsers(db *sqlx.DB, params *repo.ListRelatedUsersParams, table, userColumn, ownerColumn string) (*repo.ListRelatedUsersResult, error) {
+	result := repo.ListRelatedUsersResult{
+		Users: make([]*repo.User, 0),
+	}
+
+	args := []interface{}{params.UserID, params.Limit + 1}
+	conditions := ""
+	if params.After != nil {
+		args = append(args, params.After.CreatedAt, params.After.UserID)
+		conditions += fmt.Sprintf(" AND (f.created_at, f.%s) < ($%d, $%d)", userColumn, len(args)-1, len(args))
+	}
+	if params.Status != "" {
+		args = append(args, params.Status)
+		conditions += " AND " + statusCondition(fmt.Sprintf("$%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			%[5]s,
+			f.created_at
+		FROM %[4]s f
+		JOIN users u ON u.id = f.%[1]s
+		WHERE f.%[2]s=$1 AND u.deleted_at IS NULL%[3]s
+		ORDER BY f.created_at DESC, f.%[1]s DESC
+		LIMIT $2
+	`, userColumn, ownerColumn, conditions, table, userColumns("u"))
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var followedAt []time.Time
+	for rows.Next() {
+		var createdAt time.Time
+
+		u, err := getUser(rows, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Users = append(result.Users, u)
+		followedAt = append(followedAt, createdAt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(result.Users) > int(params.Limit) {
+		result.Users = result.Users[:params.Limit]
+		result.NextCursor = &repo.RelatedUserCursor{
+			CreatedAt: followedAt[params.Limit-1],
+			UserID:    result.Users[params.Limit-1].ID,
+		}
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,166 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollow(t *testing.T) {
+	follower := createUser(t)
+	followee := createUser(t)
+
+	created, err := strg.Follow().Follow(follower.ID, followee.ID)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	created, err = strg.Follow().Follow(follower.ID, followee.ID)
+	require.NoError(t, err)
+	require.False(t, created)
+
+	following, err := strg.Follow().IsFollowing(follower.ID, followee.ID)
+	require.NoError(t, err)
+	require.True(t, following)
+
+	u, err := strg.User().Get(followee.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), u.FollowersCount)
+
+	u, err = strg.User().Get(follower.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), u.FollowingCount)
+
+	removed, err := strg.Follow().Unfollow(follower.ID, followee.ID)
+	require.NoError(t, err)
+	require.True(t, removed)
+
+	u, err = strg.User().Get(followee.ID)
+	require.NoError(t, err)
+	require.Zero(t, u.FollowersCount)
+}
+
+func TestGetFollowers(t *testing.T) {
+	followee := createUser(t)
+	for i := 0; i < 3; i++ {
+		follower := createUser(t)
+		_, err := strg.Follow().Follow(follower.ID, followee.ID)
+		require.NoError(t, err)
+	}
+
+	first, err := strg.Follow().GetFollowers(&repo.ListRelatedUsersParams{
+		UserID: followee.ID,
+		Limit:  2,
+	})
+	require.NoError(t, err)
+	require.Len(t, first.Users, 2)
+	require.NotNil(t, first.NextCursor)
+
+	second, err := strg.Follow().GetFollowers(&repo.ListRelatedUsersParams{
+		UserID: followee.ID,
+		Limit:  2,
+		After:  first.NextCursor,
+	})
+	require.NoError(t, err)
+	require.Len(t, second.Users, 1)
+	require.Nil(t, second.NextCursor)
+}
+
+func TestGetFollowersByStatus(t *testing.T) {
+	admin := createUser(t)
+	followee := createUser(t)
+	active := createUser(t)
+	suspended := createUser(t)
+
+	for _, follower := range []*repo.User{active, suspended} {
+		_, err := strg.Follow().Follow(follower.ID, followee.ID)
+		require.NoError(t, err)
+	}
+
+	_, err := strg.User().UpdateStatus(&repo.UpdateUserStatus{
+		UserID:    suspended.ID,
+		Status:    repo.UserStatusSuspended,
+		ExpiresAt: time.Now().Add(time.Hour),
+		ActorID:   admin.ID,
+	})
+	require.NoError(t, err)
+
+	result, err := strg.Follow().GetFollowers(&repo.ListRelatedUsersParams{
+		UserID: followee.ID,
+		Limit:  10,
+		Status: repo.UserStatusActive,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Users, 1)
+	require.Equal(t, active.ID, result.Users[0].ID)
+
+	result, err = strg.Follow().GetFollowers(&repo.ListRelatedUsersParams{
+		UserID: followee.ID,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Users, 2)
+}
+
+func TestFollowCountsOfDeletedUser(t *testing.T) {
+	follower := createUser(t)
+	followee := createUser(t)
+
+	_, err := strg.Follow().Follow(follower.ID, followee.ID)
+	require.NoError(t, err)
+
+	err = strg.User().Delete(follower.ID)
+	require.NoError(t, err)
+
+	ids, err := strg.User().GetUnsyncedFollowCounts(100000)
+	require.NoError(t, err)
+	require.Contains(t, ids, follower.ID)
+
+	err = strg.User().SyncFollowCounts(follower.ID)
+	require.NoError(t, err)
+
+	u, err := strg.User().Get(followee.ID)
+	require.NoError(t, err)
+	require.Zero(t, u.FollowersCount)
+
+	// the follows of a deleted user don't move the counts again
+	removed, err := strg.Follow().Unfollow(follower.ID, followee.ID)
+	require.NoError(t, err)
+	require.True(t, removed)
+
+	u, err = strg.User().Get(followee.ID)
+	require.NoError(t, err)
+	require.Zero(t, u.FollowersCount)
+
+	_, err = strg.Follow().Follow(follower.ID, followee.ID)
+	require.NoError(t, err)
+
+	_, err = strg.User().Restore(follower.ID)
+	require.NoError(t, err)
+
+	err = strg.User().SyncFollowCounts(follower.ID)
+	require.NoError(t, err)
+
+	u, err = strg.User().Get(followee.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), u.FollowersCount)
+
+	err = strg.User().Delete(follower.ID)
+	require.NoError(t, err)
+
+	// the purge waits for the sync
+	err = strg.User().Purge(follower.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = strg.User().SyncFollowCounts(follower.ID)
+	require.NoError(t, err)
+
+	err = strg.User().Purge(follower.ID)
+	require.NoError(t, err)
+
+	u, err = strg.User().Get(followee.ID)
+	require.NoError(t, err)
+	require.Zero(t, u.FollowersCount)
+}
@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type outboxRepo struct {
+	db *sqlx.DB
+}
+
+func NewOutbox(db *sqlx.DB) repo.OutboxStorageI {
+	return &outboxRepo{
+		db: db,
+	}
+}
+
+// rowQuerier is implemented by both *sql.Tx and *sqlx.DB
+type rowQuerier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// insertOutboxEvent writes the event inside the transaction of the change it describes,
+// so the event is relayed if and only if the change is committed
+func insertOutboxEvent(q rowQuerier, eventType string, userID int64, payload interface{}) (*repo.OutboxEvent, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result := repo.OutboxEvent{
+		EventID: uuid.NewString(),
+		Type:    eventType,
+		UserID:  userID,
+		Payload: b,
+		Status:  repo.OutboxStatusPending,
+	}
+
+	var nullUserID sql.NullInt64
+	if userID != 0 {
+		nullUserID = sql.NullInt64{Int64: userID, Valid: true}
+	}
+
+	query := `
+		INSERT INTO outbox_events(
+			event_id,
+			type,
+			user_id,
+			payload
+		) VALUES($1, $2, $3, $4)
+		RETURNING id, next_attempt_at, created_at
+	`
+
+	err = q.QueryRow(
+		query,
+		result.EventID,
+		result.Type,
+		nullUserID,
+		string(result.Payload),
+	).Scan(
+		&result.ID,
+		&result.NextAttemptAt,
+		&result.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (or *outboxRepo) Create(eventType string, userID int64, payload interface{}) (*repo.OutboxEvent, error) {
+	return insertOutboxEvent(or.db, eventType, userID, payload)
+}
+
+func (or *outboxRepo) Claim(limit int, lease time.Duration) ([]*repo.OutboxEvent, error) {
+	query := `
+		UPDATE outbox_events SET
+			next_attempt_at=CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE status='pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING
+			id,
+			event_id,
+			type,
+			user_id,
+			payload,
+			status,
+			attempts,
+			last_error,
+			next_attempt_at,
+			created_at,
+			sent_at
+	`
+
+	rows, err := or.db.Query(query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.OutboxEvent, 0)
+	for rows.Next() {
+		var (
+			e         repo.OutboxEvent
+			userID    sql.NullInt64
+			payload   string
+			lastError sql.NullString
+			sentAt    sql.NullTime
+		)
+
+		err := rows.Scan(
+			&e.ID,
+			&e.EventID,
+			&e.Type,
+			&userID,
+			&payload,
+			&e.Status,
+			&e.Attempts,
+			&lastError,
+			&e.NextAttemptAt,
+			&e.CreatedAt,
+			&sentAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		e.UserID = userID.Int64
+		e.Payload = json.RawMessage(payload)
+		e.LastError = lastError.String
+		e.SentAt = sentAt.Time
+
+		result = append(result, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING doesn't keep the order of the subquery
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
+
+// redactedPayload drops the verification code from the payload once the event
+// is sent or dead-lettered, the code is useless to the outbox from then on
+var redactedPayload = fmt.Sprintf(
+	`CASE WHEN type IN ('%s', '%s') THEN payload - 'code' ELSE payload END`,
+	repo.EventUserVerificationRequested,
+	repo.EventPasswordResetRequested,
+)
+
+func (or *outboxRepo) MarkSent(id int64) error {
+	query := `
+		UPDATE outbox_events SET
+			status='sent',
+			payload=` + redactedPayload + `,
+			attempts=attempts+1,
+			last_error=NULL,
+			sent_at=CURRENT_TIMESTAMP
+		WHERE id=$1
+	`
+
+	result, err := or.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (or *outboxRepo) MarkFailed(id int64, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := repo.OutboxStatusPending
+	if dead {
+		status = repo.OutboxStatusDead
+	}
+
+	query := `
+		UPDATE outbox_events SET
+			status=$1,
+			payload=CASE WHEN $1='dead' THEN ` + redactedPayload + ` ELSE payload END,
+			attempts=attempts+1,
+			last_error=$2,
+			next_attempt_at=$3
+		WHERE id=$4
+	`
+
+	result, err := or.db.Exec(query, status, utils.NullString(lastError), nextAttemptAt, id)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/bxcodec/faker/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func claimOutboxEvent(t *testing.T, id int64) *repo.OutboxEvent {
+	events, err := strg.Outbox().Claim(1000, time.Minute)
+	require.NoError(t, err)
+
+	for _, e := range events {
+		if e.ID == id {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func TestOutbox(t *testing.T) {
+	u := createUser(t)
+
+	event, err := strg.Outbox().Create(repo.EventPasswordResetRequested, u.ID, &repo.VerificationCodePayload{
+		UserID: u.ID,
+		Email:  u.Email,
+		Code:   faker.Word(),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, event.EventID)
+
+	claimed := claimOutboxEvent(t, event.ID)
+	require.NotNil(t, claimed)
+	require.Equal(t, repo.EventPasswordResetRequested, claimed.Type)
+	require.Equal(t, u.ID, claimed.UserID)
+	require.JSONEq(t, string(event.Payload), string(claimed.Payload))
+
+	// the claimed event is leased
+	require.Nil(t, claimOutboxEvent(t, event.ID))
+
+	err = strg.Outbox().MarkFailed(event.ID, "unavailable", time.Now().Add(-time.Second), false)
+	require.NoError(t, err)
+
+	claimed = claimOutboxEvent(t, event.ID)
+	require.NotNil(t, claimed)
+	require.Equal(t, 1, claimed.Attempts)
+	require.Equal(t, "unavailable", claimed.LastError)
+
+	err = strg.Outbox().MarkSent(event.ID)
+	require.NoError(t, err)
+	require.Nil(t, claimOutboxEvent(t, event.ID))
+}
+
+func TestUserOutboxEvents(t *testing.T) {
+	u := createUser(t)
+
+	var types []string
+	events, err := strg.Outbox().Claim(1000, time.Minute)
+	require.NoError(t, err)
+	for _, e := range events {
+		if e.UserID == u.ID {
+			types = append(types, e.Type)
+		}
+	}
+	require.Equal(t, []string{repo.EventUserCreated}, types)
+}
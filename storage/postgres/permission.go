@@ -6,6 +6,7 @@ import (
 
 	"github.com/TemurMannonov/medium_user_service/storage/repo"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type permissionRepo struct {
@@ -18,14 +19,16 @@ func NewPermission(db *sqlx.DB) repo.PermissionStorageI {
 	}
 }
 
-func (ur *permissionRepo) CheckPermission(userType, resource, action string) (bool, error) {
+// CheckPermission reports whether any of the roles is allowed to perform the action on the resource
+func (ur *permissionRepo) CheckPermission(roles []string, resource, action string) (bool, error) {
 	query := `
 		SELECT id FROM permissions
-		WHERE user_type=$1 AND resource=$2 AND action=$3
+		WHERE user_type=ANY($1) AND resource=$2 AND action=$3
+		LIMIT 1
 	`
 
 	var id int64
-	err := ur.db.QueryRow(query, userType, resource, action).Scan(&id)
+	err := ur.db.QueryRow(query, pq.Array(roles), resource, action).Scan(&id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, nil
@@ -35,3 +38,152 @@ func (ur *permissionRepo) CheckPermission(userType, resource, action string) (bo
 
 	return true, nil
 }
+
+func (ur *permissionRepo) Create(p *repo.Permission, actorID int64) (*repo.Permission, error) {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO permissions(user_type, resource, action, condition)
+		VALUES($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err = tx.QueryRow(query, p.UserType, p.Resource, p.Action, p.Condition).Scan(&p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertAuditLog(tx, actorID, repo.AuditActionCreate, repo.AuditEntityPermission, p.ID, nil, p)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (ur *permissionRepo) Get(id int64) (*repo.Permission, error) {
+	var result repo.Permission
+
+	query := `SELECT id, user_type, resource, action, condition FROM permissions WHERE id=$1`
+
+	err := ur.db.QueryRow(query, id).Scan(
+		&result.ID,
+		&result.UserType,
+		&result.Resource,
+		&result.Action,
+		&result.Condition,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (ur *permissionRepo) GetAll(params *repo.GetAllPermissionsParams) ([]*repo.Permission, error) {
+	query := `
+		SELECT id, user_type, resource, action, condition FROM permissions
+		WHERE ($1 = '' OR user_type=$1) AND ($2 = '' OR resource=$2)
+		ORDER BY user_type, resource, action
+	`
+
+	rows, err := ur.db.Query(query, params.UserType, params.Resource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.Permission, 0)
+	for rows.Next() {
+		var p repo.Permission
+
+		err := rows.Scan(
+			&p.ID,
+			&p.UserType,
+			&p.Resource,
+			&p.Action,
+			&p.Condition,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &p)
+	}
+
+	return result, rows.Err()
+}
+
+func (ur *permissionRepo) Update(p *repo.Permission, actorID int64) (*repo.Permission, error) {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var old repo.Permission
+	err = tx.QueryRow(
+		`SELECT id, user_type, resource, action, condition FROM permissions WHERE id=$1 FOR UPDATE`, p.ID,
+	).Scan(&old.ID, &old.UserType, &old.Resource, &old.Action, &old.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE permissions SET
+			user_type=$1,
+			resource=$2,
+			action=$3,
+			condition=$4
+		WHERE id=$5
+	`
+
+	_, err = tx.Exec(query, p.UserType, p.Resource, p.Action, p.Condition, p.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertAuditLog(tx, actorID, repo.AuditActionUpdate, repo.AuditEntityPermission, p.ID, &old, p)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (ur *permissionRepo) Delete(id, actorID int64) error {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var old repo.Permission
+	err = tx.QueryRow(
+		`DELETE FROM permissions WHERE id=$1 RETURNING id, user_type, resource, action, condition`, id,
+	).Scan(&old.ID, &old.UserType, &old.Resource, &old.Action, &old.Condition)
+	if err != nil {
+		return err
+	}
+
+	err = insertAuditLog(tx, actorID, repo.AuditActionDelete, repo.AuditEntityPermission, id, &old, nil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
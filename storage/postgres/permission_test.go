@@ -0,0 +1,41 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/bxcodec/faker/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionAudit(t *testing.T) {
+	actor := createUser(t)
+
+	p, err := strg.Permission().Create(&repo.Permission{
+		UserType:  repo.UserTypeUser,
+		Resource:  faker.Word(),
+		Action:    "create",
+		Condition: repo.ConditionAny,
+	}, actor.ID)
+	require.NoError(t, err)
+
+	hasPermission, err := strg.Permission().CheckPermission([]string{p.UserType}, p.Resource, p.Action)
+	require.NoError(t, err)
+	require.True(t, hasPermission)
+
+	p.Action = "update"
+	_, err = strg.Permission().Update(p, actor.ID)
+	require.NoError(t, err)
+
+	err = strg.Permission().Delete(p.ID, actor.ID)
+	require.NoError(t, err)
+
+	logs, err := strg.AuditLog().GetAll(&repo.GetAllAuditLogsParams{
+		Limit:    10,
+		Page:     1,
+		Entity:   repo.AuditEntityPermission,
+		EntityID: p.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), logs.Count)
+}
@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/jmoiron/sqlx"
+)
+
+type roleRepo struct {
+	db *sqlx.DB
+}
+
+func NewRole(db *sqlx.DB) repo.RoleStorageI {
+	return &roleRepo{
+		db: db,
+	}
+}
+
+func (rr *roleRepo) Create(r *repo.Role, actorID int64) (*repo.Role, error) {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO roles(name, description)
+		VALUES($1, $2)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(query, r.Name, utils.NullString(r.Description)).Scan(&r.ID, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertAuditLog(tx, actorID, repo.AuditActionCreate, repo.AuditEntityRole, r.ID, nil, r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (rr *roleRepo) Get(id int64) (*repo.Role, error) {
+	return getRole(rr.db.QueryRow(`SELECT id, name, description, created_at FROM roles WHERE id=$1`, id))
+}
+
+func (rr *roleRepo) GetAll() ([]*repo.Role, error) {
+	rows, err := rr.db.Query(`SELECT id, name, description, created_at FROM roles ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.Role, 0)
+	for rows.Next() {
+		r, err := getRole(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	return result, rows.Err()
+}
+
+func (rr *roleRepo) Update(r *repo.Role, actorID int64) (*repo.Role, error) {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	old, err := getRole(tx.QueryRow(`SELECT id, name, description, created_at FROM roles WHERE id=$1 FOR UPDATE`, r.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE roles SET
+			name=$1,
+			description=$2
+		WHERE id=$3
+		RETURNING created_at
+	`
+
+	err = tx.QueryRow(query, r.Name, utils.NullString(r.Description), r.ID).Scan(&r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertAuditLog(tx, actorID, repo.AuditActionUpdate, repo.AuditEntityRole, r.ID, old, r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (rr *roleRepo) Delete(id, actorID int64) ([]int64, error) {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the assignments go first, the cascade would leave nothing to return
+	userIDs, err := queryIDs(tx, `DELETE FROM user_roles WHERE role_id=$1 RETURNING user_id`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := getRole(tx.QueryRow(`DELETE FROM roles WHERE id=$1 RETURNING id, name, description, created_at`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertAuditLog(tx, actorID, repo.AuditActionDelete, repo.AuditEntityRole, id, old, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func getRole(row scanner) (*repo.Role, error) {
+	var (
+		result      repo.Role
+		description sql.NullString
+	)
+
+	err := row.Scan(
+		&result.ID,
+		&result.Name,
+		&description,
+		&result.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Description = description.String
+
+	return &result, nil
+}
+
+func (rr *roleRepo) GetUserRoles(userID int64) ([]*repo.Role, error) {
+	query := `
+		SELECT r.id, r.name, r.description, r.created_at
+		FROM roles r
+		JOIN user_roles ur ON ur.role_id = r.id
+		WHERE ur.user_id=$1
+		ORDER BY r.id
+	`
+
+	rows, err := rr.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.Role, 0)
+	for rows.Next() {
+		r, err := getRole(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, r)
+	}
+
+	return result, rows.Err()
+}
+
+func (rr *roleRepo) AssignToUser(userID, roleID, actorID int64) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO user_roles(user_id, role_id) VALUES($1, $2)`, userID, roleID)
+	if err != nil {
+		return err
+	}
+
+	value := map[string]int64{"user_id": userID, "role_id": roleID}
+	err = insertAuditLog(tx, actorID, repo.AuditActionCreate, repo.AuditEntityUserRole, userID, nil, value)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (rr *roleRepo) UnassignFromUser(userID, roleID, actorID int64) error {
+	tx, err := rr.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM user_roles WHERE user_id=$1 AND role_id=$2`, userID, roleID)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return sql.ErrNoRows
+	}
+
+	value := map[string]int64{"user_id": userID, "role_id": roleID}
+	err = insertAuditLog(tx, actorID, repo.AuditActionDelete, repo.AuditEntityUserRole, userID, value, nil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/jmoiron/sqlx"
+)
+
+type sessionRepo struct {
+	db *sqlx.DB
+}
+
+func NewSession(db *sqlx.DB) repo.SessionStorageI {
+	return &sessionRepo{
+		db: db,
+	}
+}
+
+func (sr *sessionRepo) Create(session *repo.Session) (*repo.Session, error) {
+	query := `
+		INSERT INTO sessions(
+			id,
+			user_id,
+			token_id,
+			user_agent,
+			ip_address,
+			expires_at
+		) VALUES($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, last_seen_at
+	`
+
+	err := sr.db.QueryRow(
+		query,
+		session.ID,
+		session.UserID,
+		session.TokenID,
+		utils.NullString(session.UserAgent),
+		utils.NullString(session.IPAddress),
+		session.ExpiresAt,
+	).Scan(
+		&session.CreatedAt,
+		&session.LastSeenAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (sr *sessionRepo) Get(id string) (*repo.Session, error) {
+	var (
+		result               repo.Session
+		userAgent, ipAddress sql.NullString
+	)
+
+	query := `
+		SELECT
+			id,
+			user_id,
+			token_id,
+			user_agent,
+			ip_address,
+			created_at,
+			last_seen_at,
+			expires_at
+		FROM sessions
+		WHERE id=$1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+	`
+
+	err := sr.db.QueryRow(query, id).Scan(
+		&result.ID,
+		&result.UserID,
+		&result.TokenID,
+		&userAgent,
+		&ipAddress,
+		&result.CreatedAt,
+		&result.LastSeenAt,
+		&result.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.UserAgent = userAgent.String
+	result.IPAddress = ipAddress.String
+
+	return &result, nil
+}
+
+func (sr *sessionRepo) GetAllByUser(userID int64) ([]*repo.Session, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			token_id,
+			user_agent,
+			ip_address,
+			created_at,
+			last_seen_at,
+			expires_at
+		FROM sessions
+		WHERE user_id=$1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := sr.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.Session, 0)
+	for rows.Next() {
+		var (
+			s                    repo.Session
+			userAgent, ipAddress sql.NullString
+		)
+
+		err := rows.Scan(
+			&s.ID,
+			&s.UserID,
+			&s.TokenID,
+			&userAgent,
+			&ipAddress,
+			&s.CreatedAt,
+			&s.LastSeenAt,
+			&s.ExpiresAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.UserAgent = userAgent.String
+		s.IPAddress = ipAddress.String
+
+		result = append(result, &s)
+	}
+
+	return result, rows.Err()
+}
+
+func (sr *sessionRepo) UpdateToken(session *repo.Session) error {
+	query := `
+		UPDATE sessions SET
+			token_id=$1,
+			user_agent=$2,
+			ip_address=$3,
+			expires_at=$4,
+			last_seen_at=CURRENT_TIMESTAMP
+		WHERE id=$5 AND revoked_at IS NULL
+	`
+
+	result, err := sr.db.Exec(
+		query,
+		session.TokenID,
+		utils.NullString(session.UserAgent),
+		utils.NullString(session.IPAddress),
+		session.ExpiresAt,
+		session.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (sr *sessionRepo) Revoke(id string) error {
+	query := `UPDATE sessions SET revoked_at=CURRENT_TIMESTAMP WHERE id=$1 AND revoked_at IS NULL`
+
+	result, err := sr.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (sr *sessionRepo) RevokeAllByUser(userID int64) error {
+	query := `UPDATE sessions SET revoked_at=CURRENT_TIMESTAMP WHERE user_id=$1 AND revoked_at IS NULL`
+
+	_, err := sr.db.Exec(query, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
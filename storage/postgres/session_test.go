@@ -0,0 +1,40 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createSession(t *testing.T, userID int64) *repo.Session {
+	s, err := strg.Session().Create(&repo.Session{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		TokenID:   uuid.NewString(),
+		UserAgent: "grpc-go/1.50.1",
+		IPAddress: "127.0.0.1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, s)
+
+	return s
+}
+
+func TestRevokeSession(t *testing.T) {
+	u := createUser(t)
+	s := createSession(t, u.ID)
+
+	sessions, err := strg.Session().GetAllByUser(u.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	err = strg.Session().Revoke(s.ID)
+	require.NoError(t, err)
+
+	_, err = strg.Session().Get(s.ID)
+	require.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/jmoiron/sqlx"
+)
+
+type twoFactorRepo struct {
+	db *sqlx.DB
+}
+
+func NewTwoFactor(db *sqlx.DB) repo.TwoFactorStorageI {
+	return &twoFactorRepo{
+		db: db,
+	}
+}
+
+func (tr *twoFactorRepo) Get(userID int64) (*repo.TwoFactor, error) {
+	var (
+		result repo.TwoFactor
+		secret sql.NullString
+	)
+
+	query := `SELECT id, totp_secret, totp_enabled FROM users WHERE id=$1`
+
+	err := tr.db.QueryRow(query, userID).Scan(
+		&result.UserID,
+		&secret,
+		&result.Enabled,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Secret = secret.String
+
+	return &result, nil
+}
+
+func (tr *twoFactorRepo) SetSecret(userID int64, secret string) error {
+	query := `UPDATE users SET totp_secret=$1 WHERE id=$2 AND totp_enabled=FALSE`
+
+	result, err := tr.db.Exec(query, secret, userID)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (tr *twoFactorRepo) Enable(userID int64, recoveryCodeHashes []string) error {
+	tx, err := tr.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE users SET totp_enabled=TRUE WHERE id=$1 AND totp_secret IS NOT NULL`, userID)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(`DELETE FROM recovery_codes WHERE user_id=$1`, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range recoveryCodeHashes {
+		_, err = tx.Exec(`INSERT INTO recovery_codes(user_id, code_hash) VALUES($1, $2)`, userID, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (tr *twoFactorRepo) Disable(userID int64) error {
+	tx, err := tr.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`UPDATE users SET totp_enabled=FALSE, totp_secret=NULL WHERE id=$1`, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM recovery_codes WHERE user_id=$1`, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCode marks the recovery code as used and reports whether it was valid and unused
+func (tr *twoFactorRepo) UseRecoveryCode(userID int64, codeHash string) (bool, error) {
+	query := `
+		UPDATE recovery_codes SET used_at=CURRENT_TIMESTAMP
+		WHERE user_id=$1 AND code_hash=$2 AND used_at IS NULL
+	`
+
+	result, err := tr.db.Exec(query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
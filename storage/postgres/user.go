@@ -3,6 +3,10 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/TemurMannonov/medium_user_service/pkg/utils"
 	"github.com/TemurMannonov/medium_user_service/storage/repo"
@@ -20,6 +24,11 @@ func NewUser(db *sqlx.DB) repo.UserStorageI {
 }
 
 func (ur *userRepo) Create(user *repo.User) (*repo.User, error) {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
 	query := `
 		INSERT INTO users(
@@ -33,10 +42,10 @@ func (ur *userRepo) Create(user *repo.User) (*repo.User, error) {
 			profile_image_url,
 			type
 		) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id, created_at
+		RETURNING id, created_at, status
 	`
 
-	row := ur.db.QueryRow(
+	row := tx.QueryRow(
 		query,
 		user.FirstName,
 		user.LastName,
@@ -49,42 +58,77 @@ func (ur *userRepo) Create(user *repo.User) (*repo.User, error) {
 		user.Type,
 	)
 
-	err := row.Scan(
+	err = row.Scan(
 		&user.ID,
 		&user.CreatedAt,
+		&user.Status,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	_, err = tx.Exec(
+		`INSERT INTO user_roles(user_id, role_id) SELECT $1, id FROM roles WHERE name=$2`,
+		user.ID,
+		user.Type,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertUserEvent(tx, repo.EventUserCreated, user)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
-func (ur *userRepo) Get(id int64) (*repo.User, error) {
+// userColumns lists the columns read by getUser, qualified by the alias of the users table
+func userColumns(alias string) string {
+	columns := []string{
+		"id",
+		"first_name",
+		"last_name",
+		"phone_number",
+		"email",
+		"gender",
+		"password",
+		"username",
+		"profile_image_url",
+		"type",
+		"created_at",
+		"followers_count",
+		"following_count",
+		"status",
+		"status_reason",
+		"status_changed_by",
+		"status_expires_at",
+	}
+
+	for i, column := range columns {
+		columns[i] = alias + "." + column
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+// getUser scans the userColumns of the row, followed by the extra columns
+func getUser(row scanner, extra ...interface{}) (*repo.User, error) {
 	var (
 		result                                         repo.User
 		phoneNumber, gender, username, profileImageUrl sql.NullString
+		statusReason                                   sql.NullString
+		statusChangedBy                                sql.NullInt64
+		statusExpiresAt                                sql.NullTime
 	)
 
-	query := `
-		SELECT
-			id,
-			first_name,
-			last_name,
-			phone_number,
-			email,
-			gender,
-			password,
-			username,
-			profile_image_url,
-			type,
-			created_at
-		FROM users
-		WHERE id=$1
-	`
-
-	row := ur.db.QueryRow(query, id)
-	err := row.Scan(
+	dest := []interface{}{
 		&result.ID,
 		&result.FirstName,
 		&result.LastName,
@@ -96,7 +140,15 @@ func (ur *userRepo) Get(id int64) (*repo.User, error) {
 		&profileImageUrl,
 		&result.Type,
 		&result.CreatedAt,
-	)
+		&result.FollowersCount,
+		&result.FollowingCount,
+		&result.Status,
+		&statusReason,
+		&statusChangedBy,
+		&statusExpiresAt,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -106,47 +158,155 @@ func (ur *userRepo) Get(id int64) (*repo.User, error) {
 	result.Username = username.String
 	result.ProfileImageUrl = profileImageUrl.String
 
+	// the status lifts by itself once it expires
+	if statusExpiresAt.Valid && !statusExpiresAt.Time.After(time.Now()) {
+		result.Status = repo.UserStatusActive
+	} else {
+		result.StatusReason = statusReason.String
+		result.StatusChangedBy = statusChangedBy.Int64
+		result.StatusExpiresAt = statusExpiresAt.Time
+	}
+
 	return &result, nil
 }
 
+func (ur *userRepo) Get(id int64) (*repo.User, error) {
+	query := `
+		SELECT
+			` + userColumns("users") + `
+		FROM users
+		WHERE id=$1 AND deleted_at IS NULL
+	`
+
+	return getUser(ur.db.QueryRow(query, id))
+}
+
+var userSortColumns = map[string]string{
+	repo.UserSortCreatedAt: "created_at",
+	repo.UserSortFirstName: "first_name",
+	repo.UserSortLastName:  "last_name",
+	repo.UserSortUsername:  "username",
+}
+
+// prefixTsQuery builds a to_tsquery expression matching every word of the search as a prefix.
+// The words are quoted, so the operators of the tsquery syntax are taken literally.
+func prefixTsQuery(search string) string {
+	words := strings.Fields(search)
+	for i, word := range words {
+		word = strings.ReplaceAll(word, `\`, `\\`)
+		word = strings.ReplaceAll(word, "'", "''")
+		words[i] = "'" + word + "':*"
+	}
+
+	return strings.Join(words, " & ")
+}
+
+// statusCondition matches the users by their effective status, see getUser
+func statusCondition(status string) string {
+	return fmt.Sprintf(
+		"(CASE WHEN status_expires_at <= CURRENT_TIMESTAMP THEN 'active' ELSE status END) = %s",
+		status,
+	)
+}
+
+// searchCondition matches the users by the full text of their names and username,
+// or by the similarity of the name and the username, which tolerates typos.
+// The email is matched by its prefix when searchEmail is set.
+func searchCondition(tsQuery, search string, searchEmail bool) string {
+	var email string
+	if searchEmail {
+		email = fmt.Sprintf(" OR starts_with(lower(email), lower(%s))", search)
+	}
+
+	return fmt.Sprintf(
+		`(search_vector @@ to_tsquery('simple', %[1]s)
+			OR (first_name || ' ' || last_name) %% %[2]s OR username %% %[2]s%[3]s)`,
+		tsQuery, search, email,
+	)
+}
+
 func (ur *userRepo) GetAll(params *repo.GetAllUsersParams) (*repo.GetAllUsersResult, error) {
 	result := repo.GetAllUsersResult{
 		Users: make([]*repo.User, 0),
 	}
 
-	offset := (params.Page - 1) * params.Limit
+	var (
+		conditions = []string{"deleted_at IS NULL"}
+		args       []interface{}
+	)
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if strings.TrimSpace(params.Search) != "" {
+		conditions = append(conditions, searchCondition(arg(prefixTsQuery(params.Search)), arg(params.Search), params.SearchEmail))
+	}
+	if params.Type != "" {
+		conditions = append(conditions, "type="+arg(params.Type))
+	}
+	if params.Gender != "" {
+		conditions = append(conditions, "gender="+arg(params.Gender))
+	}
+	if params.Status != "" {
+		conditions = append(conditions, statusCondition(arg(params.Status)))
+	}
+	if !params.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(params.CreatedFrom))
+	}
+	if !params.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at <= "+arg(params.CreatedTo))
+	}
+	if params.HasUsername != nil {
+		if *params.HasUsername {
+			conditions = append(conditions, "username IS NOT NULL")
+		} else {
+			conditions = append(conditions, "username IS NULL")
+		}
+	}
+
+	filter := " WHERE " + strings.Join(conditions, " AND ")
+	countArgs := len(args)
 
-	limit := fmt.Sprintf(" LIMIT %d OFFSET %d ", params.Limit, offset)
+	sortColumn, ok := userSortColumns[params.SortBy]
+	if !ok {
+		sortColumn = userSortColumns[repo.UserSortCreatedAt]
+	}
+	sortOrder := "DESC"
+	if params.SortOrder == repo.SortOrderAsc {
+		sortOrder = "ASC"
+	}
 
-	filter := ""
-	if params.Search != "" {
-		str := "%" + params.Search + "%"
-		filter += fmt.Sprintf(`
-			WHERE first_name ILIKE '%s' OR last_name ILIKE '%s' OR email ILIKE '%s' 
-				OR username ILIKE '%s' OR phone_number ILIKE '%s'`,
-			str, str, str, str, str,
-		)
+	var limit string
+	if params.Page > 0 {
+		offset := (params.Page - 1) * params.Limit
+		limit = fmt.Sprintf(" LIMIT %s OFFSET %s ", arg(params.Limit), arg(offset))
+	} else {
+		// the keyset follows the (created_at, id) order, one more row tells whether a next page exists
+		sortColumn = "created_at"
+		if params.After != nil {
+			operator := "<"
+			if sortOrder == "ASC" {
+				operator = ">"
+			}
+			conditions = append(conditions, fmt.Sprintf(
+				"(created_at, id) %s (%s, %s)", operator, arg(params.After.CreatedAt), arg(params.After.ID),
+			))
+		}
+		limit = fmt.Sprintf(" LIMIT %s ", arg(params.Limit+1))
 	}
 
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
 	query := `
 		SELECT
-			id,
-			first_name,
-			last_name,
-			phone_number,
-			email,
-			gender,
-			password,
-			username,
-			profile_image_url,
-			type,
-			created_at
+			` + userColumns("users") + `
 		FROM users
-		` + filter + `
-		ORDER BY created_at desc
+		` + where + `
+		ORDER BY ` + sortColumn + ` ` + sortOrder + `, id ` + sortOrder + `
 		` + limit
 
-	rows, err := ur.db.Query(query)
+	rows, err := ur.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -154,153 +314,483 @@ func (ur *userRepo) GetAll(params *repo.GetAllUsersParams) (*repo.GetAllUsersRes
 	defer rows.Close()
 
 	for rows.Next() {
-		var (
-			u                                              repo.User
-			phoneNumber, gender, username, profileImageUrl sql.NullString
-		)
-
-		err := rows.Scan(
-			&u.ID,
-			&u.FirstName,
-			&u.LastName,
-			&phoneNumber,
-			&u.Email,
-			&gender,
-			&u.Password,
-			&username,
-			&profileImageUrl,
-			&u.Type,
-			&u.CreatedAt,
-		)
+		u, err := getUser(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		u.PhoneNumber = phoneNumber.String
-		u.Gender = gender.String
-		u.Username = username.String
-		u.ProfileImageUrl = profileImageUrl.String
+		result.Users = append(result.Users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		result.Users = append(result.Users, &u)
+	if params.Page <= 0 && len(result.Users) > int(params.Limit) {
+		result.Users = result.Users[:params.Limit]
+		last := result.Users[len(result.Users)-1]
+		result.NextCursor = &repo.UserCursor{
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		}
 	}
 
-	queryCount := `SELECT count(1) FROM users ` + filter
-	err = ur.db.QueryRow(queryCount).Scan(&result.Count)
-	if err != nil {
-		return nil, err
+	if params.WithCount {
+		queryCount := `SELECT count(1) FROM users ` + filter
+		err = ur.db.QueryRow(queryCount, args[:countArgs]...).Scan(&result.Count)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &result, nil
 }
 
-func (ur *userRepo) GetByEmail(email string) (*repo.User, error) {
-	var (
-		result                                         repo.User
-		phoneNumber, gender, username, profileImageUrl sql.NullString
-	)
+// ts_headline marks the matches with control characters, which can't appear in names,
+// so the snippet can be escaped before the marks are turned into tags
+const (
+	highlightStart = "\x01"
+	highlightStop  = "\x02"
+)
+
+var highlighter = strings.NewReplacer(highlightStart, "<b>", highlightStop, "</b>")
+
+// highlight returns the html escaped snippet with the matches wrapped in <b></b>
+func highlight(snippet string) string {
+	return highlighter.Replace(html.EscapeString(snippet))
+}
+
+func (ur *userRepo) Search(params *repo.SearchUsersParams) ([]*repo.UserSearchResult, error) {
+	result := make([]*repo.UserSearchResult, 0)
+
+	if strings.TrimSpace(params.Query) == "" {
+		return result, nil
+	}
 
 	query := `
 		SELECT
-			id,
-			first_name,
-			last_name,
-			phone_number,
-			email,
-			gender,
-			password,
-			username,
-			profile_image_url,
-			type,
-			created_at
+			` + userColumns("users") + `,
+			ts_headline(
+				'simple',
+				first_name || ' ' || last_name || coalesce(' @' || username, ''),
+				to_tsquery('simple', $1),
+				$4
+			) AS snippet,
+			ts_rank(search_vector, to_tsquery('simple', $1)) + greatest(
+				similarity(first_name || ' ' || last_name, $2),
+				coalesce(similarity(username, $2), 0)
+			) AS rank
 		FROM users
-		WHERE email=$1
+		WHERE deleted_at IS NULL AND ` + searchCondition("$1", "$2", params.SearchEmail) + `
+			AND ($5 = '' OR ` + statusCondition("$5") + `)
+		ORDER BY rank DESC, id
+		LIMIT $3
 	`
 
-	row := ur.db.QueryRow(query, email)
-	err := row.Scan(
-		&result.ID,
-		&result.FirstName,
-		&result.LastName,
-		&phoneNumber,
-		&result.Email,
-		&gender,
-		&result.Password,
-		&username,
-		&profileImageUrl,
-		&result.Type,
-		&result.CreatedAt,
-	)
+	headlineOptions := fmt.Sprintf("StartSel=%s, StopSel=%s, HighlightAll=true", highlightStart, highlightStop)
+
+	rows, err := ur.db.Query(query, prefixTsQuery(params.Query), params.Query, params.Limit, headlineOptions, params.Status)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	result.PhoneNumber = phoneNumber.String
-	result.Gender = gender.String
-	result.Username = username.String
-	result.ProfileImageUrl = profileImageUrl.String
+	for rows.Next() {
+		var r repo.UserSearchResult
 
-	return &result, nil
+		r.User, err = getUser(rows, &r.Snippet, &r.Rank)
+		if err != nil {
+			return nil, err
+		}
+
+		r.Snippet = highlight(r.Snippet)
+		result = append(result, &r)
+	}
+
+	return result, rows.Err()
 }
 
-func (ur *userRepo) UpdatePassword(req *repo.UpdatePassword) error {
-	query := `UPDATE users SET password=$1 WHERE id=$2`
+func (ur *userRepo) GetByEmail(email string) (*repo.User, error) {
+	query := `
+		SELECT
+			` + userColumns("users") + `
+		FROM users
+		WHERE email=$1 AND deleted_at IS NULL
+	`
 
-	_, err := ur.db.Exec(query, req.Password, req.UserID)
+	return getUser(ur.db.QueryRow(query, email))
+}
+
+func (ur *userRepo) UpdatePassword(req *repo.UpdatePassword) error {
+	tx, err := ur.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	return nil
-}
+	query := `
+		UPDATE users SET password=$1
+		WHERE id=$2 AND deleted_at IS NULL
+		RETURNING
+			` + userColumns("users") + `
+	`
 
-func (ur *userRepo) Delete(id int64) error {
-	query := `DELETE FROM users WHERE id=$1`
+	user, err := getUser(tx.QueryRow(query, req.Password, req.UserID))
+	if err != nil {
+		return err
+	}
 
-	result, err := ur.db.Exec(query, id)
+	err = insertUserEvent(tx, repo.EventUserUpdated, user, repo.UserFieldPassword)
 	if err != nil {
 		return err
 	}
 
-	if count, _ := result.RowsAffected(); count == 0 {
-		return sql.ErrNoRows
+	return tx.Commit()
+}
+
+type userStatusLog struct {
+	Status    string     `json:"status"`
+	Reason    string     `json:"reason,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func newUserStatusLog(u *repo.User) userStatusLog {
+	result := userStatusLog{
+		Status: u.Status,
+		Reason: u.StatusReason,
+	}
+
+	if !u.StatusExpiresAt.IsZero() {
+		result.ExpiresAt = &u.StatusExpiresAt
 	}
 
-	return nil
+	return result
 }
 
-func (ur *userRepo) Update(user *repo.User) (*repo.User, error) {
+// UpdateStatus changes the status of the user and records the change in the audit log
+func (ur *userRepo) UpdateStatus(req *repo.UpdateUserStatus) (*repo.User, error) {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	old, err := getUser(tx.QueryRow(`
+		SELECT `+userColumns("users")+`
+		FROM users
+		WHERE id=$1 AND deleted_at IS NULL
+		FOR UPDATE`,
+		req.UserID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt sql.NullTime
+	if !req.ExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: req.ExpiresAt, Valid: true}
+	}
+
+	var changedBy sql.NullInt64
+	if req.ActorID != 0 {
+		changedBy = sql.NullInt64{Int64: req.ActorID, Valid: true}
+	}
+
 	query := `
 		UPDATE users SET
-			first_name=$1,
-			last_name=$2,
-			phone_number=$3,
-			gender=$4,
-			username=$5,
-			profile_image_url=$6
-		WHERE id=$7
-		RETURNING 
-			email,
-			type,
-			created_at
+			status=$1,
+			status_reason=$2,
+			status_changed_by=$3,
+			status_expires_at=$4
+		WHERE id=$5
+		RETURNING
+			` + userColumns("users") + `
 	`
 
-	err := ur.db.QueryRow(
+	user, err := getUser(tx.QueryRow(
 		query,
-		user.FirstName,
-		user.LastName,
-		user.PhoneNumber,
-		user.Gender,
-		user.Username,
-		user.ProfileImageUrl,
-		user.ID,
-	).Scan(
-		&user.Email,
-		&user.Type,
-		&user.CreatedAt,
+		req.Status,
+		utils.NullString(req.Reason),
+		changedBy,
+		expiresAt,
+		req.UserID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertAuditLog(
+		tx,
+		req.ActorID,
+		repo.AuditActionUpdate,
+		repo.AuditEntityUserStatus,
+		req.UserID,
+		newUserStatusLog(old),
+		newUserStatusLog(user),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	err = insertUserEvent(tx, repo.EventUserUpdated, user, repo.UserFieldStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Delete marks the user as deleted, the user is kept until it's purged
+func (ur *userRepo) Delete(id int64) error {
+	_, err := ur.setDeletedAt(id, `CURRENT_TIMESTAMP`, `deleted_at IS NULL`)
+	return err
+}
+
+func (ur *userRepo) Restore(id int64) (*repo.User, error) {
+	return ur.setDeletedAt(id, `NULL`, `deleted_at IS NOT NULL`)
+}
+
+// setDeletedAt soft deletes or restores the user, the user.deleted event is left to Purge,
+// as the user can still be restored until then. The counts of the users on the other side
+// of its follows are moved later by SyncFollowCounts.
+func (ur *userRepo) setDeletedAt(id int64, value, condition string) (*repo.User, error) {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users SET deleted_at=` + value + `
+		WHERE id=$1 AND ` + condition + `
+		RETURNING
+			` + userColumns("users") + `
+	`
+
+	user, err := getUser(tx.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertUserEvent(tx, repo.EventUserUpdated, user, repo.UserFieldDeletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
+
+func (ur *userRepo) GetDeletedBefore(before time.Time, limit int32) ([]int64, error) {
+	query := `
+		SELECT id FROM users
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1 AND NOT follows_counted
+		ORDER BY deleted_at
+		LIMIT $2
+	`
+
+	return queryIDs(ur.db, query, before, limit)
+}
+
+// querier is implemented by both *sql.Tx and *sqlx.DB
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func queryIDs(q querier, query string, args ...interface{}) ([]int64, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, id)
+	}
+
+	return result, rows.Err()
+}
+
+func (ur *userRepo) GetUnsyncedFollowCounts(limit int32) ([]int64, error) {
+	query := `
+		SELECT id FROM users
+		WHERE follows_counted = (deleted_at IS NOT NULL)
+		ORDER BY id
+		LIMIT $1
+	`
+
+	return queryIDs(ur.db, query, limit)
+}
+
+// SyncFollowCounts moves the follows of the user in or out of the counts of the users on the
+// other side, after the user was restored or deleted. The user is locked like in Follow,
+// so the follows of the user don't change meanwhile, the other users are updated in one statement.
+// The lock timeout gives way to the follows waiting for the locks held here, the sync is retried later.
+func (ur *userRepo) SyncFollowCounts(id int64) error {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`SET LOCAL lock_timeout = '200ms'`)
+	if err != nil {
+		return err
+	}
+
+	var live, counted bool
+	err = tx.QueryRow(
+		`SELECT deleted_at IS NULL, follows_counted FROM users WHERE id=$1 FOR NO KEY UPDATE`,
+		id,
+	).Scan(&live, &counted)
+	if err != nil {
+		return err
+	}
+
+	if live == counted {
+		return nil
+	}
+
+	delta := -1
+	if live {
+		delta = 1
+	}
+
+	_, err = tx.Exec(`
+		UPDATE users SET followers_count=followers_count+$1
+		WHERE id IN (SELECT followee_id FROM follows WHERE follower_id=$2)`,
+		delta,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE users SET following_count=following_count+$1
+		WHERE id IN (SELECT follower_id FROM follows WHERE followee_id=$2)`,
+		delta,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE users SET follows_counted=$1 WHERE id=$2`, live, id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Purge removes the soft deleted user for good
+func (ur *userRepo) Purge(id int64) error {
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the follows of the user are deleted by cascade, the counts of the other side
+	// left the user out once its deletion was synced
+	user, err := getUser(tx.QueryRow(`
+		DELETE FROM users WHERE id=$1 AND deleted_at IS NOT NULL AND NOT follows_counted
+		RETURNING `+userColumns("users"),
+		id,
+	))
+	if err != nil {
+		return err
+	}
+
+	err = scrubUserEvents(tx, user)
+	if err != nil {
+		return err
+	}
+
+	// the event of the purge carries only the id
+	err = insertUserEvent(tx, repo.EventUserDeleted, &repo.User{ID: user.ID})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (ur *userRepo) Update(user *repo.User, fields []string) (*repo.User, error) {
+	if len(fields) == 0 {
+		return ur.Get(user.ID)
+	}
+
+	var (
+		set    []string
+		values []interface{}
+	)
+	for _, field := range fields {
+		var value interface{}
+		switch field {
+		case repo.UserFieldFirstName:
+			value = user.FirstName
+		case repo.UserFieldLastName:
+			value = user.LastName
+		case repo.UserFieldPhoneNumber:
+			value = utils.NullString(user.PhoneNumber)
+		case repo.UserFieldGender:
+			value = utils.NullString(user.Gender)
+		case repo.UserFieldUsername:
+			value = utils.NullString(user.Username)
+		case repo.UserFieldProfileImageUrl:
+			value = utils.NullString(user.ProfileImageUrl)
+		default:
+			return nil, fmt.Errorf("unknown user field: %s", field)
+		}
+
+		values = append(values, value)
+		set = append(set, fmt.Sprintf("%s=$%d", field, len(values)))
+	}
+	values = append(values, user.ID)
+
+	query := `
+		UPDATE users SET ` + strings.Join(set, ", ") + `
+		WHERE id=$` + strconv.Itoa(len(values)) + ` AND deleted_at IS NULL
+		RETURNING
+			` + userColumns("users") + `
+	`
+
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := getUser(tx.QueryRow(query, values...))
+	if err != nil {
+		return nil, err
+	}
+
+	err = insertUserEvent(tx, repo.EventUserUpdated, result, fields...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// userEventPersonalFields are the fields of the payloads scrubbed once the user is purged
+var userEventPersonalFields = []string{"first_name", "last_name", "email", "username"}
+
+type userEventRepo struct {
+	db *sqlx.DB
+}
+
+func NewUserEvent(db *sqlx.DB) repo.UserEventStorageI {
+	return &userEventRepo{
+		db: db,
+	}
+}
+
+// newUserEventPayload leaves the password and the private fields out of the event
+func newUserEventPayload(u *repo.User, fields ...string) *repo.UserEventPayload {
+	return &repo.UserEventPayload{
+		ID:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+		Username:  u.Username,
+		Type:      u.Type,
+		Status:    u.Status,
+		Fields:    fields,
+	}
+}
+
+// insertUserEvent appends the event to the user event log and queues it in the outbox,
+// inside the transaction of the change it describes. The event records the id of the
+// transaction, GetAfter orders the log by it.
+func insertUserEvent(tx *sql.Tx, eventType string, user *repo.User, fields ...string) error {
+	event, err := insertOutboxEvent(tx, eventType, user.ID, newUserEventPayload(user, fields...))
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO user_events(
+			event_id,
+			type,
+			user_id,
+			payload,
+			created_at
+		) VALUES($1, $2, $3, $4, $5)
+	`
+
+	_, err = tx.Exec(
+		query,
+		event.EventID,
+		event.Type,
+		event.UserID,
+		string(event.Payload),
+		event.CreatedAt,
+	)
+	return err
+}
+
+// scrubUserEvents removes the personal fields of the purged user from the logged events,
+// the outbox and the webhook deliveries. The verification emails queued before the user
+// was created are found by the email.
+func scrubUserEvents(tx *sql.Tx, user *repo.User) error {
+	fields := pq.Array(userEventPersonalFields)
+
+	_, err := tx.Exec(`
+		UPDATE webhook_deliveries SET payload=jsonb_set(payload, '{data}', (payload->'data') - $1::text[])
+		WHERE payload ? 'data' AND event_id IN (SELECT event_id FROM user_events WHERE user_id=$2)`,
+		fields,
+		user.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE user_events SET payload=payload - $1::text[] WHERE user_id=$2`, fields, user.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE outbox_events SET payload=payload - $1::text[]
+		WHERE user_id=$2 OR payload->>'email'=$3`,
+		fields,
+		user.ID,
+		user.Email,
+	)
+	return err
+}
+
+// GetAfter reads the log in the order of the transactions writing the events, up to the
+// oldest transaction still running. The transactions before it have all ended, so an event
+// committed later always comes after the ones already read and a reader never skips one.
+// A long running transaction holds the log back until it ends.
+func (er *userEventRepo) GetAfter(params *repo.GetUserEventsParams) ([]*repo.UserEvent, error) {
+	var after struct {
+		xactID   string
+		sequence int64
+	}
+	if params.AfterSequence != 0 {
+		err := er.db.QueryRow(
+			`SELECT xact_id::text, sequence FROM user_events WHERE sequence=$1`,
+			params.AfterSequence,
+		).Scan(&after.xactID, &after.sequence)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+		SELECT
+			sequence,
+			event_id,
+			type,
+			user_id,
+			payload,
+			created_at
+		FROM user_events
+		WHERE xact_id < pg_snapshot_xmin(pg_current_snapshot())
+			AND ($1 = '' OR (xact_id, sequence) > ($1::xid8, $2))
+			AND (cardinality($3::varchar[]) = 0 OR type=ANY($3))
+		ORDER BY xact_id, sequence
+		LIMIT $4
+	`
+
+	rows, err := er.db.Query(query, after.xactID, after.sequence, pq.Array(params.Types), params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.UserEvent, 0)
+	for rows.Next() {
+		var (
+			e       repo.UserEvent
+			payload []byte
+		)
+
+		err := rows.Scan(
+			&e.Sequence,
+			&e.EventID,
+			&e.Type,
+			&e.UserID,
+			&payload,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(payload, &e.Payload)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &e)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,91 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/bxcodec/faker/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserEvents(t *testing.T) {
+	events, err := strg.UserEvent().GetAfter(&repo.GetUserEventsParams{Limit: 1})
+	require.NoError(t, err)
+
+	var after int64
+	for len(events) > 0 {
+		after = events[len(events)-1].Sequence
+		events, err = strg.UserEvent().GetAfter(&repo.GetUserEventsParams{AfterSequence: after, Limit: 1000})
+		require.NoError(t, err)
+	}
+
+	u := createUser(t)
+
+	_, err = strg.User().Update(&repo.User{
+		ID:        u.ID,
+		FirstName: faker.FirstName(),
+	}, []string{repo.UserFieldFirstName})
+	require.NoError(t, err)
+
+	err = strg.User().Delete(u.ID)
+	require.NoError(t, err)
+
+	events, err = strg.UserEvent().GetAfter(&repo.GetUserEventsParams{AfterSequence: after, Limit: 1000})
+	require.NoError(t, err)
+
+	var own []*repo.UserEvent
+	for i, e := range events {
+		if i > 0 {
+			require.Greater(t, e.Sequence, events[i-1].Sequence)
+		}
+		if e.UserID == u.ID {
+			own = append(own, e)
+		}
+	}
+	require.Len(t, own, 3)
+	require.Equal(t, repo.EventUserCreated, own[0].Type)
+	require.Equal(t, u.Email, own[0].Payload.Email)
+	require.Equal(t, []string{repo.UserFieldFirstName}, own[1].Payload.Fields)
+	require.Equal(t, []string{repo.UserFieldDeletedAt}, own[2].Payload.Fields)
+
+	events, err = strg.UserEvent().GetAfter(&repo.GetUserEventsParams{
+		AfterSequence: after,
+		Types:         []string{repo.EventUserCreated},
+		Limit:         1000,
+	})
+	require.NoError(t, err)
+	for _, e := range events {
+		require.Equal(t, repo.EventUserCreated, e.Type)
+	}
+}
+
+func TestPurgeScrubsUserEvents(t *testing.T) {
+	u := createUser(t)
+
+	err := strg.User().Delete(u.ID)
+	require.NoError(t, err)
+
+	err = strg.User().SyncFollowCounts(u.ID)
+	require.NoError(t, err)
+
+	err = strg.User().Purge(u.ID)
+	require.NoError(t, err)
+
+	events, err := strg.UserEvent().GetAfter(&repo.GetUserEventsParams{Limit: 100000})
+	require.NoError(t, err)
+
+	var own []*repo.UserEvent
+	for _, e := range events {
+		if e.UserID == u.ID {
+			own = append(own, e)
+		}
+	}
+	require.NotEmpty(t, own)
+	for _, e := range own {
+		require.Equal(t, u.ID, e.Payload.ID)
+		require.Empty(t, e.Payload.Email)
+		require.Empty(t, e.Payload.FirstName)
+		require.Empty(t, e.Payload.LastName)
+	}
+	require.Equal(t, repo.EventUserDeleted, own[len(own)-1].Type)
+}
@@ -1,7 +1,9 @@
 package postgres_test
 
 import (
+	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/TemurMannonov/medium_user_service/storage/repo"
 	"github.com/bxcodec/faker/v4"
@@ -25,3 +27,218 @@ func createUser(t *testing.T) *repo.User {
 func TestCreateUser(t *testing.T) {
 	createUser(t)
 }
+
+func TestUpdateUser(t *testing.T) {
+	u := createUser(t)
+
+	updated, err := strg.User().Update(&repo.User{
+		ID:          u.ID,
+		FirstName:   faker.FirstName(),
+		PhoneNumber: faker.Phonenumber(),
+		Username:    faker.Username(),
+	}, []string{repo.UserFieldPhoneNumber, repo.UserFieldUsername})
+	require.NoError(t, err)
+
+	require.Equal(t, u.FirstName, updated.FirstName)
+	require.Equal(t, u.LastName, updated.LastName)
+	require.NotEmpty(t, updated.PhoneNumber)
+	require.NotEmpty(t, updated.Username)
+
+	updated, err = strg.User().Update(&repo.User{
+		ID:        u.ID,
+		FirstName: faker.FirstName(),
+	}, []string{repo.UserFieldFirstName})
+	require.NoError(t, err)
+	require.NotEmpty(t, updated.PhoneNumber)
+	require.NotEmpty(t, updated.Username)
+
+	_, err = strg.User().Update(&repo.User{ID: u.ID}, []string{"password"})
+	require.Error(t, err)
+}
+
+func TestGetAllUsers(t *testing.T) {
+	u := createUser(t)
+
+	hasUsername := false
+	result, err := strg.User().GetAll(&repo.GetAllUsersParams{
+		Limit:       10,
+		Page:        1,
+		Search:      u.Email,
+		SearchEmail: true,
+		Type:        repo.UserTypeUser,
+		CreatedFrom: u.CreatedAt.Add(-time.Minute),
+		HasUsername: &hasUsername,
+		SortBy:      repo.UserSortFirstName,
+		SortOrder:   repo.SortOrderAsc,
+		WithCount:   true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result.Count)
+	require.Equal(t, u.ID, result.Users[0].ID)
+
+	// the email is matched only when asked for
+	result, err = strg.User().GetAll(&repo.GetAllUsersParams{
+		Limit:     10,
+		Page:      1,
+		Search:    u.Email,
+		WithCount: true,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Count)
+
+	result, err = strg.User().GetAll(&repo.GetAllUsersParams{
+		Limit:     10,
+		Page:      1,
+		Search:    "' OR 1=1 --",
+		WithCount: true,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Count)
+}
+
+func TestGetAllUsersByCursor(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		createUser(t)
+	}
+
+	params := repo.GetAllUsersParams{
+		Limit:     2,
+		SortBy:    repo.UserSortCreatedAt,
+		SortOrder: repo.SortOrderDesc,
+	}
+
+	first, err := strg.User().GetAll(&params)
+	require.NoError(t, err)
+	require.Len(t, first.Users, 2)
+	require.NotNil(t, first.NextCursor)
+	require.Zero(t, first.Count)
+
+	params.After = first.NextCursor
+	second, err := strg.User().GetAll(&params)
+	require.NoError(t, err)
+	require.NotEmpty(t, second.Users)
+
+	last := first.Users[len(first.Users)-1]
+	for _, u := range second.Users {
+		require.NotEqual(t, last.ID, u.ID)
+		require.False(t, u.CreatedAt.After(last.CreatedAt))
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	u, err := strg.User().Create(&repo.User{
+		FirstName: "Xanthippe",
+		LastName:  "Q",
+		Email:     faker.Email(),
+		Password:  faker.Password(),
+		Type:      repo.UserTypeUser,
+	})
+	require.NoError(t, err)
+
+	results, err := strg.User().Search(&repo.SearchUsersParams{
+		Query: "xanth",
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	require.Equal(t, u.ID, results[0].User.ID)
+	require.Contains(t, results[0].Snippet, "<b>Xanthippe</b>")
+
+	results, err = strg.User().Search(&repo.SearchUsersParams{
+		Query: "Xantippe Q",
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+}
+
+func TestUpdatePassword(t *testing.T) {
+	u := createUser(t)
+
+	err := strg.User().UpdatePassword(&repo.UpdatePassword{
+		UserID:   u.ID,
+		Password: faker.Password(),
+	})
+	require.NoError(t, err)
+
+	err = strg.User().Delete(u.ID)
+	require.NoError(t, err)
+
+	err = strg.User().UpdatePassword(&repo.UpdatePassword{
+		UserID:   u.ID,
+		Password: faker.Password(),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestDeleteUser(t *testing.T) {
+	u := createUser(t)
+
+	err := strg.User().Delete(u.ID)
+	require.NoError(t, err)
+
+	_, err = strg.User().Get(u.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	restored, err := strg.User().Restore(u.ID)
+	require.NoError(t, err)
+	require.Equal(t, u.Email, restored.Email)
+
+	err = strg.User().Delete(u.ID)
+	require.NoError(t, err)
+
+	err = strg.User().SyncFollowCounts(u.ID)
+	require.NoError(t, err)
+
+	ids, err := strg.User().GetDeletedBefore(time.Now().Add(time.Minute), 1000)
+	require.NoError(t, err)
+	require.Contains(t, ids, u.ID)
+
+	err = strg.User().Purge(u.ID)
+	require.NoError(t, err)
+
+	err = strg.User().Purge(u.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUpdateUserStatus(t *testing.T) {
+	admin := createUser(t)
+	u := createUser(t)
+	require.Equal(t, repo.UserStatusActive, u.Status)
+
+	updated, err := strg.User().UpdateStatus(&repo.UpdateUserStatus{
+		UserID:    u.ID,
+		Status:    repo.UserStatusSuspended,
+		Reason:    "spam",
+		ExpiresAt: time.Now().Add(time.Hour),
+		ActorID:   admin.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, repo.UserStatusSuspended, updated.Status)
+	require.Equal(t, "spam", updated.StatusReason)
+	require.Equal(t, admin.ID, updated.StatusChangedBy)
+
+	result, err := strg.User().GetAll(&repo.GetAllUsersParams{
+		Limit:       10,
+		Page:        1,
+		Search:      u.Email,
+		SearchEmail: true,
+		Status:      repo.UserStatusActive,
+	})
+	require.NoError(t, err)
+	require.Empty(t, result.Users)
+
+	// an expired suspension reads as active
+	_, err = strg.User().UpdateStatus(&repo.UpdateUserStatus{
+		UserID:    u.ID,
+		Status:    repo.UserStatusSuspended,
+		ExpiresAt: time.Now().Add(-time.Second),
+		ActorID:   admin.ID,
+	})
+	require.NoError(t, err)
+
+	got, err := strg.User().Get(u.ID)
+	require.NoError(t, err)
+	require.True(t, got.IsActive())
+	require.Empty(t, got.StatusReason)
+}
@@ -0,0 +1,410 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/pkg/utils"
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type webhookRepo struct {
+	db *sqlx.DB
+}
+
+func NewWebhook(db *sqlx.DB) repo.WebhookStorageI {
+	return &webhookRepo{
+		db: db,
+	}
+}
+
+func (wr *webhookRepo) Create(w *repo.Webhook, actorID int64) (*repo.Webhook, error) {
+	tx, err := wr.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var createdBy sql.NullInt64
+	if actorID != 0 {
+		createdBy = sql.NullInt64{Int64: actorID, Valid: true}
+	}
+
+	query := `
+		INSERT INTO webhooks(
+			url,
+			secret,
+			event_types,
+			active,
+			created_by
+		) VALUES($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(
+		query,
+		w.URL,
+		w.Secret,
+		pq.Array(w.EventTypes),
+		w.Active,
+		createdBy,
+	).Scan(
+		&w.ID,
+		&w.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	w.CreatedBy = actorID
+
+	err = insertAuditLog(tx, actorID, repo.AuditActionCreate, repo.AuditEntityWebhook, w.ID, nil, w)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+const webhookColumns = `id, url, secret, event_types, active, created_by, created_at`
+
+func getWebhook(row scanner) (*repo.Webhook, error) {
+	var (
+		result    repo.Webhook
+		createdBy sql.NullInt64
+	)
+
+	err := row.Scan(
+		&result.ID,
+		&result.URL,
+		&result.Secret,
+		pq.Array(&result.EventTypes),
+		&result.Active,
+		&createdBy,
+		&result.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.CreatedBy = createdBy.Int64
+
+	return &result, nil
+}
+
+func (wr *webhookRepo) Get(id int64) (*repo.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id=$1`
+
+	return getWebhook(wr.db.QueryRow(query, id))
+}
+
+func (wr *webhookRepo) GetAll() ([]*repo.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks ORDER BY id`
+
+	rows, err := wr.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.Webhook, 0)
+	for rows.Next() {
+		w, err := getWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, w)
+	}
+
+	return result, rows.Err()
+}
+
+func (wr *webhookRepo) Delete(id, actorID int64) error {
+	tx, err := wr.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	old, err := getWebhook(tx.QueryRow(`DELETE FROM webhooks WHERE id=$1 RETURNING `+webhookColumns, id))
+	if err != nil {
+		return err
+	}
+
+	err = insertAuditLog(tx, actorID, repo.AuditActionDelete, repo.AuditEntityWebhook, id, old, nil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (wr *webhookRepo) CreateDeliveries(eventID, eventType string, payload json.RawMessage) error {
+	query := `
+		INSERT INTO webhook_deliveries(
+			webhook_id,
+			event_id,
+			event_type,
+			payload
+		)
+		SELECT id, $1, $2, $3 FROM webhooks
+		WHERE active AND $2=ANY(event_types)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+
+	_, err := wr.db.Exec(query, eventID, eventType, string(payload))
+	return err
+}
+
+const webhookDeliveryColumns = `
+	d.id,
+	d.webhook_id,
+	d.event_id,
+	d.event_type,
+	d.payload,
+	d.status,
+	d.attempts,
+	d.response_status,
+	d.last_error,
+	d.next_attempt_at,
+	d.created_at,
+	d.delivered_at
+`
+
+func getWebhookDelivery(row scanner, extra ...interface{}) (*repo.WebhookDelivery, error) {
+	var (
+		result         repo.WebhookDelivery
+		payload        string
+		responseStatus sql.NullInt64
+		lastError      sql.NullString
+		deliveredAt    sql.NullTime
+	)
+
+	dest := []interface{}{
+		&result.ID,
+		&result.WebhookID,
+		&result.EventID,
+		&result.EventType,
+		&payload,
+		&result.Status,
+		&result.Attempts,
+		&responseStatus,
+		&lastError,
+		&result.NextAttemptAt,
+		&result.CreatedAt,
+		&deliveredAt,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Payload = json.RawMessage(payload)
+	result.ResponseStatus = int(responseStatus.Int64)
+	result.LastError = lastError.String
+	result.DeliveredAt = deliveredAt.Time
+
+	return &result, nil
+}
+
+func (wr *webhookRepo) ClaimDeliveries(limit int, lease time.Duration) ([]*repo.WebhookDelivery, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE webhook_deliveries SET
+				next_attempt_at=CURRENT_TIMESTAMP + $2 * INTERVAL '1 millisecond'
+			WHERE id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status='pending' AND next_attempt_at <= CURRENT_TIMESTAMP
+				ORDER BY id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT
+			` + webhookDeliveryColumns + `,
+			w.url,
+			w.secret
+		FROM claimed d
+		JOIN webhooks w ON w.id=d.webhook_id
+		ORDER BY d.id
+	`
+
+	rows, err := wr.db.Query(query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.WebhookDelivery, 0)
+	for rows.Next() {
+		var w repo.Webhook
+
+		d, err := getWebhookDelivery(rows, &w.URL, &w.Secret)
+		if err != nil {
+			return nil, err
+		}
+
+		w.ID = d.WebhookID
+		d.Webhook = &w
+
+		result = append(result, d)
+	}
+
+	return result, rows.Err()
+}
+
+func (wr *webhookRepo) MarkDelivered(id int64, responseStatus int) error {
+	query := `
+		UPDATE webhook_deliveries SET
+			status='succeeded',
+			attempts=attempts+1,
+			response_status=$1,
+			last_error=NULL,
+			delivered_at=CURRENT_TIMESTAMP
+		WHERE id=$2
+	`
+
+	result, err := wr.db.Exec(query, responseStatus, id)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (wr *webhookRepo) MarkDeliveryFailed(id int64, responseStatus int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := repo.WebhookDeliveryPending
+	if dead {
+		status = repo.WebhookDeliveryFailed
+	}
+
+	var nullResponseStatus sql.NullInt64
+	if responseStatus != 0 {
+		nullResponseStatus = sql.NullInt64{Int64: int64(responseStatus), Valid: true}
+	}
+
+	query := `
+		UPDATE webhook_deliveries SET
+			status=$1,
+			attempts=attempts+1,
+			response_status=$2,
+			last_error=$3,
+			next_attempt_at=$4
+		WHERE id=$5
+	`
+
+	result, err := wr.db.Exec(query, status, nullResponseStatus, utils.NullString(lastError), nextAttemptAt, id)
+	if err != nil {
+		return err
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (wr *webhookRepo) GetDeliveries(params *repo.GetAllWebhookDeliveriesParams) (*repo.GetAllWebhookDeliveriesResult, error) {
+	result := repo.GetAllWebhookDeliveriesResult{
+		Deliveries: make([]*repo.WebhookDelivery, 0),
+	}
+
+	var (
+		filter = " WHERE true "
+		args   = make([]interface{}, 0)
+	)
+
+	if params.WebhookID != 0 {
+		args = append(args, params.WebhookID)
+		filter += fmt.Sprintf(" AND d.webhook_id=$%d ", len(args))
+	}
+
+	if params.Status != "" {
+		args = append(args, params.Status)
+		filter += fmt.Sprintf(" AND d.status=$%d ", len(args))
+	}
+
+	offset := (params.Page - 1) * params.Limit
+	limit := fmt.Sprintf(" LIMIT %d OFFSET %d ", params.Limit, offset)
+
+	query := `
+		SELECT
+			` + webhookDeliveryColumns + `
+		FROM webhook_deliveries d
+		` + filter + `
+		ORDER BY d.id DESC
+		` + limit
+
+	rows, err := wr.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d, err := getWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Deliveries = append(result.Deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	queryCount := `SELECT count(1) FROM webhook_deliveries d ` + filter
+	err = wr.db.QueryRow(queryCount, args...).Scan(&result.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (wr *webhookRepo) ReplayDelivery(id int64) (*repo.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d SET
+			status='pending',
+			attempts=0,
+			next_attempt_at=CURRENT_TIMESTAMP,
+			delivered_at=NULL
+		WHERE id=$1 AND status<>'pending'
+		RETURNING
+			` + webhookDeliveryColumns
+
+	delivery, err := getWebhookDelivery(wr.db.QueryRow(query, id))
+	if !errors.Is(err, sql.ErrNoRows) {
+		return delivery, err
+	}
+
+	// a pending delivery may be leased by a dispatcher sending it right now
+	var exists bool
+	err = wr.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM webhook_deliveries WHERE id=$1)`, id).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, repo.ErrDeliveryPending
+	}
+
+	return nil, sql.ErrNoRows
+}
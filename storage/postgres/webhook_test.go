@@ -0,0 +1,120 @@
+package postgres_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/TemurMannonov/medium_user_service/storage/repo"
+	"github.com/bxcodec/faker/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createWebhook(t *testing.T, eventTypes ...string) *repo.Webhook {
+	actor := createUser(t)
+
+	w, err := strg.Webhook().Create(&repo.Webhook{
+		URL:        faker.URL(),
+		Secret:     faker.Password(),
+		EventTypes: eventTypes,
+		Active:     true,
+	}, actor.ID)
+	require.NoError(t, err)
+	require.NotZero(t, w.ID)
+
+	return w
+}
+
+func claimWebhookDelivery(t *testing.T, id int64) *repo.WebhookDelivery {
+	deliveries, err := strg.Webhook().ClaimDeliveries(1000, time.Minute)
+	require.NoError(t, err)
+
+	for _, d := range deliveries {
+		if d.ID == id {
+			return d
+		}
+	}
+
+	return nil
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	created := createWebhook(t, repo.EventUserCreated)
+	deleted := createWebhook(t, repo.EventUserDeleted)
+
+	w, err := strg.Webhook().Get(created.ID)
+	require.NoError(t, err)
+	require.Equal(t, created.URL, w.URL)
+	require.Equal(t, created.Secret, w.Secret)
+	require.Equal(t, []string{repo.EventUserCreated}, w.EventTypes)
+
+	eventID := uuid.NewString()
+	payload := json.RawMessage(`{"type":"user.created"}`)
+
+	// queueing the same event again is a no-op
+	for i := 0; i < 2; i++ {
+		err = strg.Webhook().CreateDeliveries(eventID, repo.EventUserCreated, payload)
+		require.NoError(t, err)
+	}
+
+	result, err := strg.Webhook().GetDeliveries(&repo.GetAllWebhookDeliveriesParams{
+		Limit:     10,
+		Page:      1,
+		WebhookID: created.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result.Count)
+	delivery := result.Deliveries[0]
+	require.Equal(t, eventID, delivery.EventID)
+	require.Equal(t, repo.WebhookDeliveryPending, delivery.Status)
+
+	result, err = strg.Webhook().GetDeliveries(&repo.GetAllWebhookDeliveriesParams{
+		Limit:     10,
+		Page:      1,
+		WebhookID: deleted.ID,
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Count)
+
+	claimed := claimWebhookDelivery(t, delivery.ID)
+	require.NotNil(t, claimed)
+	require.Equal(t, created.URL, claimed.Webhook.URL)
+	require.Equal(t, created.Secret, claimed.Webhook.Secret)
+	require.JSONEq(t, string(payload), string(claimed.Payload))
+
+	err = strg.Webhook().MarkDeliveryFailed(delivery.ID, 503, "unavailable", time.Now().Add(time.Hour), true)
+	require.NoError(t, err)
+	require.Nil(t, claimWebhookDelivery(t, delivery.ID))
+
+	replayed, err := strg.Webhook().ReplayDelivery(delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.WebhookDeliveryPending, replayed.Status)
+	require.Zero(t, replayed.Attempts)
+	require.Equal(t, 503, replayed.ResponseStatus)
+
+	_, err = strg.Webhook().ReplayDelivery(delivery.ID)
+	require.ErrorIs(t, err, repo.ErrDeliveryPending)
+
+	require.NotNil(t, claimWebhookDelivery(t, delivery.ID))
+
+	err = strg.Webhook().MarkDelivered(delivery.ID, 200)
+	require.NoError(t, err)
+
+	result, err = strg.Webhook().GetDeliveries(&repo.GetAllWebhookDeliveriesParams{
+		Limit:     10,
+		Page:      1,
+		WebhookID: created.ID,
+		Status:    repo.WebhookDeliverySucceeded,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result.Count)
+	require.Equal(t, 200, result.Deliveries[0].ResponseStatus)
+	require.False(t, result.Deliveries[0].DeliveredAt.IsZero())
+
+	err = strg.Webhook().Delete(created.ID, created.CreatedBy)
+	require.NoError(t, err)
+
+	_, err = strg.Webhook().Get(created.ID)
+	require.Error(t, err)
+}
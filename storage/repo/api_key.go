@@ -0,0 +1,39 @@
+package repo
+
+import "time"
+
+type ApiKeyScope struct {
+	Resource string
+	Action   string
+}
+
+type ApiKey struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []*ApiKeyScope
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+}
+
+// HasScope reports whether the key is allowed to perform the action on the resource
+func (k *ApiKey) HasScope(resource, action string) bool {
+	for _, scope := range k.Scopes {
+		if scope.Resource == resource && scope.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+type ApiKeyStorageI interface {
+	Create(k *ApiKey) (*ApiKey, error)
+	GetByHash(keyHash string) (*ApiKey, error)
+	GetAllByUser(userID int64) ([]*ApiKey, error)
+	UpdateLastUsed(id int64) error
+	Revoke(id, userID int64) error
+	RevokeAllByUser(userID int64) error
+}
@@ -0,0 +1,42 @@
+package repo
+
+import "time"
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+
+	AuditEntityPermission = "permission"
+	AuditEntityRole       = "role"
+	AuditEntityUserRole   = "user_role"
+	AuditEntityUserStatus = "user_status"
+	AuditEntityWebhook    = "webhook"
+)
+
+type AuditLog struct {
+	ID        int64
+	ActorID   int64
+	Action    string
+	Entity    string
+	EntityID  int64
+	OldValue  string
+	NewValue  string
+	CreatedAt time.Time
+}
+
+type GetAllAuditLogsParams struct {
+	Limit    int32
+	Page     int32
+	Entity   string
+	EntityID int64
+}
+
+type GetAllAuditLogsResult struct {
+	AuditLogs []*AuditLog
+	Count     int32
+}
+
+type AuditLogStorageI interface {
+	GetAll(params *GetAllAuditLogsParams) (*GetAllAuditLogsResult, error)
+}
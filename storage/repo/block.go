@@ -0,0 +1,27 @@
+package repo
+
+// Interaction describes the blocks and mutes between an actor and a target user
+type Interaction struct {
+	// Blocking is set when the actor blocked the target
+	Blocking bool
+	// BlockedBy is set when the target blocked the actor
+	BlockedBy bool
+	// Muting is set when the actor muted the target
+	Muting bool
+	// MutedBy is set when the target muted the actor
+	MutedBy bool
+}
+
+type BlockStorageI interface {
+	// Block removes the follows between the users both ways. It reports false if the user
+	// is already blocked.
+	Block(blockerID, blockedID int64) (bool, error)
+	Unblock(blockerID, blockedID int64) (bool, error)
+	// GetBlocked lists the users blocked by params.UserID, the latest first
+	GetBlocked(params *ListRelatedUsersParams) (*ListRelatedUsersResult, error)
+	Mute(muterID, mutedID int64) (bool, error)
+	Unmute(muterID, mutedID int64) (bool, error)
+	// GetMuted lists the users muted by params.UserID, the latest first
+	GetMuted(params *ListRelatedUsersParams) (*ListRelatedUsersResult, error)
+	GetInteraction(actorID, targetID int64) (*Interaction, error)
+}
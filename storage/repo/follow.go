@@ -0,0 +1,13 @@
+package repo
+
+type FollowStorageI interface {
+	// Follow reports false if the user already follows the followee or a block exists between them
+	Follow(followerID, followeeID int64) (bool, error)
+	// Unfollow reports false if the user doesn't follow the followee
+	Unfollow(followerID, followeeID int64) (bool, error)
+	IsFollowing(followerID, followeeID int64) (bool, error)
+	// GetFollowers lists the users following params.UserID, the latest first
+	GetFollowers(params *ListRelatedUsersParams) (*ListRelatedUsersResult, error)
+	// GetFollowing lists the users params.UserID follows, the latest first
+	GetFollowing(params *ListRelatedUsersParams) (*ListRelatedUsersResult, error)
+}
@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventUserCreated               = "user.created"
+	EventUserUpdated               = "user.updated"
+	EventUserDeleted               = "user.deleted"
+	EventUserVerificationRequested = "user.verification_requested"
+	EventPasswordResetRequested    = "password.reset_requested"
+)
+
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	// OutboxStatusDead marks the events that ran out of attempts, they are kept for inspection
+	OutboxStatusDead = "dead"
+)
+
+type OutboxEvent struct {
+	ID            int64
+	EventID       string
+	Type          string
+	UserID        int64
+	Payload       json.RawMessage
+	Status        string
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	SentAt        time.Time
+}
+
+// UserEventPayload is the payload of the user.created, user.updated and user.deleted events
+type UserEventPayload struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Type      string `json:"type,omitempty"`
+	Status    string `json:"status,omitempty"`
+	// Fields lists the changed fields of user.updated
+	Fields []string `json:"fields,omitempty"`
+}
+
+// VerificationCodePayload is the payload of the events delivering a code by email
+type VerificationCodePayload struct {
+	UserID int64  `json:"user_id,omitempty"`
+	Email  string `json:"email"`
+	Code   string `json:"code"`
+}
+
+type OutboxStorageI interface {
+	Create(eventType string, userID int64, payload interface{}) (*OutboxEvent, error)
+	// Claim leases up to limit due pending events, the lease keeps other dispatchers away
+	// from them until it expires
+	Claim(limit int, lease time.Duration) ([]*OutboxEvent, error)
+	MarkSent(id int64) error
+	// MarkFailed schedules the next attempt, or dead-letters the event when dead is set
+	MarkFailed(id int64, lastError string, nextAttemptAt time.Time, dead bool) error
+}
@@ -1,5 +1,33 @@
 package repo
 
+// Conditions limit the resources a permission applies to
+const (
+	// ConditionAny allows the action on every resource
+	ConditionAny = "any"
+	// ConditionOwn allows the action only on resources owned by the user
+	ConditionOwn = "own"
+)
+
+// Permission grants an action on a resource to a role. UserType holds the role name,
+// since every user type is a role as well.
+type Permission struct {
+	ID        int64  `json:"id"`
+	UserType  string `json:"user_type"`
+	Resource  string `json:"resource"`
+	Action    string `json:"action"`
+	Condition string `json:"condition"`
+}
+
+type GetAllPermissionsParams struct {
+	UserType string
+	Resource string
+}
+
 type PermissionStorageI interface {
-	CheckPermission(userType, resource, action string) (bool, error)
+	CheckPermission(roles []string, resource, action string) (bool, error)
+	Create(p *Permission, actorID int64) (*Permission, error)
+	Get(id int64) (*Permission, error)
+	GetAll(params *GetAllPermissionsParams) ([]*Permission, error)
+	Update(p *Permission, actorID int64) (*Permission, error)
+	Delete(id, actorID int64) error
 }
@@ -0,0 +1,22 @@
+package repo
+
+import "time"
+
+type Role struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type RoleStorageI interface {
+	Create(r *Role, actorID int64) (*Role, error)
+	Get(id int64) (*Role, error)
+	GetAll() ([]*Role, error)
+	Update(r *Role, actorID int64) (*Role, error)
+	// Delete removes the role and returns the users it was assigned to
+	Delete(id, actorID int64) ([]int64, error)
+	GetUserRoles(userID int64) ([]*Role, error)
+	AssignToUser(userID, roleID, actorID int64) error
+	UnassignFromUser(userID, roleID, actorID int64) error
+}
@@ -0,0 +1,23 @@
+package repo
+
+import "time"
+
+type Session struct {
+	ID         string
+	UserID     int64
+	TokenID    string
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
+
+type SessionStorageI interface {
+	Create(s *Session) (*Session, error)
+	Get(id string) (*Session, error)
+	GetAllByUser(userID int64) ([]*Session, error)
+	UpdateToken(s *Session) error
+	Revoke(id string) error
+	RevokeAllByUser(userID int64) error
+}
@@ -0,0 +1,15 @@
+package repo
+
+type TwoFactor struct {
+	UserID  int64
+	Secret  string
+	Enabled bool
+}
+
+type TwoFactorStorageI interface {
+	Get(userID int64) (*TwoFactor, error)
+	SetSecret(userID int64, secret string) error
+	Enable(userID int64, recoveryCodeHashes []string) error
+	Disable(userID int64) error
+	UseRecoveryCode(userID int64, codeHash string) (bool, error)
+}
@@ -7,6 +7,14 @@ const (
 	UserTypeUser       = "user"
 )
 
+const (
+	UserStatusActive      = "active"
+	UserStatusPending     = "pending"
+	UserStatusSuspended   = "suspended"
+	UserStatusBanned      = "banned"
+	UserStatusDeactivated = "deactivated"
+)
+
 type User struct {
 	ID              int64
 	FirstName       string
@@ -19,17 +27,125 @@ type User struct {
 	ProfileImageUrl string
 	Type            string
 	CreatedAt       time.Time
+	FollowersCount  int32
+	FollowingCount  int32
+	// Status is the effective status, a status whose expiry has passed reads as active
+	Status          string
+	StatusReason    string
+	StatusChangedBy int64
+	StatusExpiresAt time.Time
+}
+
+func (u *User) IsActive() bool {
+	return u.Status == UserStatusActive
+}
+
+// Fields GetAll can sort the users by
+const (
+	UserSortCreatedAt = "created_at"
+	UserSortFirstName = "first_name"
+	UserSortLastName  = "last_name"
+	UserSortUsername  = "username"
+)
+
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// UserCursor is the position of the last user of a page in the (created_at, id) order
+type UserCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        int64     `json:"i"`
 }
 
 type GetAllUsersParams struct {
-	Limit  int32
+	Limit int32
+	// Page selects offset pagination, the users are listed after the cursor when it is zero
 	Page   int32
+	After  *UserCursor
 	Search string
+	// SearchEmail matches the search against the email too, for the callers allowed to see it
+	SearchEmail bool
+	Type        string
+	Gender      string
+	Status      string
+	// CreatedFrom and CreatedTo are ignored when zero
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// HasUsername is ignored when nil
+	HasUsername *bool
+	SortBy      string
+	SortOrder   string
+	WithCount   bool
 }
 
 type GetAllUsersResult struct {
 	Users []*User
 	Count int32
+	// NextCursor is set in cursor mode when more users follow
+	NextCursor *UserCursor
+}
+
+// Fields of the user that can be changed by Update
+const (
+	UserFieldFirstName       = "first_name"
+	UserFieldLastName        = "last_name"
+	UserFieldPhoneNumber     = "phone_number"
+	UserFieldGender          = "gender"
+	UserFieldUsername        = "username"
+	UserFieldProfileImageUrl = "profile_image_url"
+)
+
+// Fields of the user changed by the other methods, reported in the user.updated events
+const (
+	UserFieldPassword  = "password"
+	UserFieldStatus    = "status"
+	UserFieldDeletedAt = "deleted_at"
+)
+
+// RelatedUserCursor is the position of the last user of a page of follows, blocks or mutes
+// in the (related at, user id) order
+type RelatedUserCursor struct {
+	CreatedAt time.Time `json:"c"`
+	UserID    int64     `json:"i"`
+}
+
+type ListRelatedUsersParams struct {
+	UserID int64
+	Limit  int32
+	After  *RelatedUserCursor
+	// Status filters the users by their effective status, empty matches all
+	Status string
+}
+
+type ListRelatedUsersResult struct {
+	Users []*User
+	// NextCursor is set when there is another page of users
+	NextCursor *RelatedUserCursor
+}
+
+type SearchUsersParams struct {
+	Query  string
+	Limit  int32
+	Status string
+	// SearchEmail matches the query against the email too, for the callers allowed to see it
+	SearchEmail bool
+}
+
+type UserSearchResult struct {
+	User    *User
+	Snippet string
+	Rank    float64
+}
+
+type UpdateUserStatus struct {
+	UserID int64
+	Status string
+	Reason string
+	// ExpiresAt lifts the status when it passes, it's ignored when zero
+	ExpiresAt time.Time
+	ActorID   int64
 }
 
 type UpdatePassword struct {
@@ -42,7 +158,26 @@ type UserStorageI interface {
 	Get(id int64) (*User, error)
 	GetByEmail(email string) (*User, error)
 	GetAll(params *GetAllUsersParams) (*GetAllUsersResult, error)
+	// Search returns the users matching the query by full text or by similarity, most relevant first
+	Search(params *SearchUsersParams) ([]*UserSearchResult, error)
+	// UpdatePassword returns sql.ErrNoRows when the user doesn't exist or is deleted
 	UpdatePassword(req *UpdatePassword) error
-	Update(u *User) (*User, error)
+	UpdateStatus(req *UpdateUserStatus) (*User, error)
+	// Update changes only the listed fields of the user
+	Update(u *User, fields []string) (*User, error)
+	// Delete marks the user as deleted, deleted users are excluded from every read
 	Delete(id int64) error
+	Restore(id int64) (*User, error)
+	// GetDeletedBefore returns the ids of the users deleted before the time, the oldest first.
+	// The users whose follows are still counted by SyncFollowCounts are left out.
+	GetDeletedBefore(before time.Time, limit int32) ([]int64, error)
+	// Purge removes a deleted user with its data. It returns sql.ErrNoRows if the user is
+	// not deleted or has already been purged.
+	Purge(id int64) error
+	// GetUnsyncedFollowCounts returns the ids of the users deleted or restored since
+	// their follows were last synced
+	GetUnsyncedFollowCounts(limit int32) ([]int64, error)
+	// SyncFollowCounts leaves the follows of a deleted user out of the follow counts of the
+	// other users, and puts them back once the user is restored
+	SyncFollowCounts(id int64) error
 }
@@ -0,0 +1,27 @@
+package repo
+
+import "time"
+
+// UserEvent is an entry of the user event log, the log keeps the user.created,
+// user.updated and user.deleted events in the order they were committed
+type UserEvent struct {
+	Sequence  int64
+	EventID   string
+	Type      string
+	UserID    int64
+	Payload   UserEventPayload
+	CreatedAt time.Time
+}
+
+type GetUserEventsParams struct {
+	AfterSequence int64
+	// empty matches all the types
+	Types []string
+	Limit int32
+}
+
+type UserEventStorageI interface {
+	// GetAfter returns the events committed after the event with the AfterSequence, or
+	// sql.ErrNoRows when there is no such event
+	GetAfter(params *GetUserEventsParams) ([]*UserEvent, error)
+}
@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	// WebhookDeliveryFailed marks the deliveries that ran out of attempts, they can be replayed
+	WebhookDeliveryFailed = "failed"
+)
+
+// ErrDeliveryPending is returned when replaying a delivery that is still queued or being sent
+var ErrDeliveryPending = errors.New("webhook delivery is pending")
+
+type Webhook struct {
+	ID         int64
+	URL        string
+	Secret     string `json:"-"`
+	EventTypes []string
+	Active     bool
+	CreatedBy  int64
+	CreatedAt  time.Time
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	WebhookID      int64
+	EventID        string
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int
+	ResponseStatus int
+	LastError      string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    time.Time
+	// Webhook is set by ClaimDeliveries only
+	Webhook *Webhook
+}
+
+type GetAllWebhookDeliveriesParams struct {
+	Limit     int32
+	Page      int32
+	WebhookID int64
+	Status    string
+}
+
+type GetAllWebhookDeliveriesResult struct {
+	Deliveries []*WebhookDelivery
+	Count      int32
+}
+
+type WebhookStorageI interface {
+	Create(w *Webhook, actorID int64) (*Webhook, error)
+	Get(id int64) (*Webhook, error)
+	GetAll() ([]*Webhook, error)
+	Delete(id, actorID int64) error
+	// CreateDeliveries queues the event for the active webhooks subscribed to its type,
+	// an event is queued once per webhook however many times it's passed
+	CreateDeliveries(eventID, eventType string, payload json.RawMessage) error
+	// ClaimDeliveries leases up to limit due pending deliveries, like OutboxStorageI.Claim
+	ClaimDeliveries(limit int, lease time.Duration) ([]*WebhookDelivery, error)
+	MarkDelivered(id int64, responseStatus int) error
+	// MarkDeliveryFailed schedules the next attempt, or fails the delivery when dead is set
+	MarkDeliveryFailed(id int64, responseStatus int, lastError string, nextAttemptAt time.Time, dead bool) error
+	GetDeliveries(params *GetAllWebhookDeliveriesParams) (*GetAllWebhookDeliveriesResult, error)
+	// ReplayDelivery queues the delivery again with a fresh set of attempts,
+	// it returns ErrDeliveryPending for the deliveries that are still pending
+	ReplayDelivery(id int64) (*WebhookDelivery, error)
+}
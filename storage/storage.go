@@ -9,17 +9,47 @@ import (
 type StorageI interface {
 	User() repo.UserStorageI
 	Permission() repo.PermissionStorageI
+	Session() repo.SessionStorageI
+	TwoFactor() repo.TwoFactorStorageI
+	ApiKey() repo.ApiKeyStorageI
+	Role() repo.RoleStorageI
+	AuditLog() repo.AuditLogStorageI
+	Follow() repo.FollowStorageI
+	Block() repo.BlockStorageI
+	Outbox() repo.OutboxStorageI
+	UserEvent() repo.UserEventStorageI
+	Webhook() repo.WebhookStorageI
 }
 
 type storagePg struct {
 	userRepo       repo.UserStorageI
 	permissionRepo repo.PermissionStorageI
+	sessionRepo    repo.SessionStorageI
+	twoFactorRepo  repo.TwoFactorStorageI
+	apiKeyRepo     repo.ApiKeyStorageI
+	roleRepo       repo.RoleStorageI
+	auditLogRepo   repo.AuditLogStorageI
+	followRepo     repo.FollowStorageI
+	blockRepo      repo.BlockStorageI
+	outboxRepo     repo.OutboxStorageI
+	userEventRepo  repo.UserEventStorageI
+	webhookRepo    repo.WebhookStorageI
 }
 
 func NewStoragePg(db *sqlx.DB) StorageI {
 	return &storagePg{
 		userRepo:       postgres.NewUser(db),
 		permissionRepo: postgres.NewPermission(db),
+		sessionRepo:    postgres.NewSession(db),
+		twoFactorRepo:  postgres.NewTwoFactor(db),
+		apiKeyRepo:     postgres.NewApiKey(db),
+		roleRepo:       postgres.NewRole(db),
+		auditLogRepo:   postgres.NewAuditLog(db),
+		followRepo:     postgres.NewFollow(db),
+		blockRepo:      postgres.NewBlock(db),
+		outboxRepo:     postgres.NewOutbox(db),
+		userEventRepo:  postgres.NewUserEvent(db),
+		webhookRepo:    postgres.NewWebhook(db),
 	}
 }
 
@@ -30,3 +60,43 @@ func (s *storagePg) User() repo.UserStorageI {
 func (s *storagePg) Permission() repo.PermissionStorageI {
 	return s.permissionRepo
 }
+
+func (s *storagePg) Session() repo.SessionStorageI {
+	return s.sessionRepo
+}
+
+func (s *storagePg) TwoFactor() repo.TwoFactorStorageI {
+	return s.twoFactorRepo
+}
+
+func (s *storagePg) ApiKey() repo.ApiKeyStorageI {
+	return s.apiKeyRepo
+}
+
+func (s *storagePg) Role() repo.RoleStorageI {
+	return s.roleRepo
+}
+
+func (s *storagePg) AuditLog() repo.AuditLogStorageI {
+	return s.auditLogRepo
+}
+
+func (s *storagePg) Follow() repo.FollowStorageI {
+	return s.followRepo
+}
+
+func (s *storagePg) Block() repo.BlockStorageI {
+	return s.blockRepo
+}
+
+func (s *storagePg) Outbox() repo.OutboxStorageI {
+	return s.outboxRepo
+}
+
+func (s *storagePg) UserEvent() repo.UserEventStorageI {
+	return s.userEventRepo
+}
+
+func (s *storagePg) Webhook() repo.WebhookStorageI {
+	return s.webhookRepo
+}